@@ -0,0 +1,176 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"hash/crc32"
+	"strconv"
+	"strings"
+
+	"github.com/KEINOS/go-genrawid/pkg/hasher"
+	"github.com/pkg/errors"
+)
+
+// Names of the polynomials of --crc-poly option. The others are given in hex.
+const (
+	polyCastagnoli = "castagnoli"
+	polyIEEE       = "ieee"
+	polyKoopman    = "koopman"
+)
+
+// ----------------------------------------------------------------------------
+//  Private Functions
+// ----------------------------------------------------------------------------
+
+// It validates --hash, --hash-len and --crc-poly options and sets them to the
+// hasher package. The length of the hash is validated before reading the input.
+//
+// It must be called after chkOptChkSum.
+func chkOptAlgo() error {
+	algo, err := hasher.ParseHashAlgo(inHash)
+	if err != nil {
+		return errors.Wrap(err, "invalid --hash option")
+	}
+
+	poly, err := parseCRCPoly(inCRCPoly)
+	if err != nil {
+		return errors.Wrap(err, "invalid --crc-poly option")
+	}
+
+	if poly != crc32.Castagnoli && hasher.ChkSumAlgo != hasher.ChkSumCRC32 {
+		return errors.New("--crc-poly option is meaningful only with crc32 checksum")
+	}
+
+	if isFast && isAlgoChanged(algo, poly) {
+		return errors.New("--hash, --hash-len, --checksum and --crc-poly options can not be used with --fast option")
+	}
+
+	conf := hasher.Config{HashAlgo: algo, HashLen: inHashLen, ChkSumAlgo: hasher.ChkSumAlgo, CRC32Poly: poly}
+	if err := chkHashLen(conf); err != nil {
+		return errors.Wrap(err, "invalid --hash-len option")
+	}
+
+	hasher.HashAlgo = algo
+	hasher.HashLen = inHashLen
+	hasher.CRC32Poly = poly
+
+	return nil
+}
+
+// It returns true if any of the algorithm options differs from the default. The
+// parsed values are compared, so that such as "Castagnoli" and "0x82f63b78" are
+// the same as the default polynomial.
+func isAlgoChanged(algo hasher.THashAlgo, poly uint32) bool {
+	return algo != hasher.HashAlgos()[0] ||
+		inHashLen != 0 ||
+		hasher.ChkSumAlgo != hasher.ChkSumAlgos()[0] ||
+		poly != crc32.Castagnoli
+}
+
+// It returns an error if the hash of the length can not be computed, or is too
+// short to fill a rawid with the checksum. The rawid takes the checksum up to 4
+// bytes and the hash the rest of 8 bytes. The lengths are of the empty input.
+func chkHashLen(conf hasher.Config) error {
+	const (
+		lenByte   = 8
+		lenSumMax = 4
+	)
+
+	digest, err := conf.Hash(strings.NewReader(""))
+	if err != nil {
+		return err
+	}
+
+	sum, err := conf.CheckSum(bytes.NewReader(digest))
+	if err != nil {
+		return err
+	}
+
+	lenSum := len(sum)
+	if lenSum > lenSumMax {
+		lenSum = lenSumMax
+	}
+
+	if len(digest) < lenByte-lenSum {
+		return &hasher.ErrInvalidLength{Given: len(digest), Min: lenByte - lenSum}
+	}
+
+	return nil
+}
+
+// It returns the polynomial of CRC32 of the name or of the hex with "0x" prefix,
+// such as "0x82f63b78".
+func parseCRCPoly(name string) (uint32, error) {
+	const prefixHex = "0x"
+
+	switch strings.ToLower(name) {
+	case polyCastagnoli:
+		return crc32.Castagnoli, nil
+	case polyIEEE:
+		return crc32.IEEE, nil
+	case polyKoopman:
+		return crc32.Koopman, nil
+	}
+
+	if !strings.HasPrefix(strings.ToLower(name), prefixHex) {
+		return 0, errors.Errorf("unknown polynomial: %s. it must be %s, %s, %s or in hex with %q prefix",
+			name, polyCastagnoli, polyIEEE, polyKoopman, prefixHex)
+	}
+
+	poly, err := strconv.ParseUint(name[len(prefixHex):], 16, 32)
+	if err != nil || poly == 0 {
+		return 0, errors.Errorf("invalid polynomial: %s. it must be a non-zero 32 bit hex", name)
+	}
+
+	return uint32(poly), nil
+}
+
+// It prints the algorithms available for --hash, --checksum and --crc-poly
+// options. The first one of each is the default.
+func runListAlgorithms() {
+	const markDefault = " (default)"
+
+	//nolint:forbidigo // allow printing to stdout
+	printList := func(title string, names []string) {
+		fmt.Println(title)
+
+		for i, name := range names {
+			if i == 0 {
+				name += markDefault
+			}
+
+			fmt.Println("  " + name)
+		}
+	}
+
+	printList("Hash algorithms (--hash):", namesHashAlgo())
+	printList("Checksum algorithms (--checksum):", namesChkSumAlgo())
+	printList("CRC32 polynomials (--crc-poly, or in hex such as 0x82f63b78):", namesCRCPoly())
+}
+
+// It returns the names of the hash algorithms. The first one is the default.
+func namesHashAlgo() []string {
+	names := make([]string, 0, len(hasher.HashAlgos()))
+
+	for _, algo := range hasher.HashAlgos() {
+		names = append(names, algo.String())
+	}
+
+	return names
+}
+
+// It returns the names of the checksum algorithms. The first one is the default.
+func namesChkSumAlgo() []string {
+	names := make([]string, 0, len(hasher.ChkSumAlgos()))
+
+	for _, algo := range hasher.ChkSumAlgos() {
+		names = append(names, algo.String())
+	}
+
+	return names
+}
+
+// It returns the names of the polynomials of CRC32. The first one is the default.
+func namesCRCPoly() []string {
+	return []string{polyCastagnoli, polyIEEE, polyKoopman}
+}
@@ -0,0 +1,259 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/KEINOS/go-genrawid"
+	"github.com/KEINOS/go-genrawid/pkg/hasher"
+	"github.com/KEINOS/go-genrawid/pkg/rawid"
+	"github.com/pkg/errors"
+)
+
+// ----------------------------------------------------------------------------
+//  Type: checkStats
+// ----------------------------------------------------------------------------
+
+// checkStats is the number of the results of the --check option.
+type checkStats struct {
+	numFailed  int // the rawids did not match.
+	numMissing int // the files not found or could not be read.
+	numInvalid int // the lines improperly formatted.
+}
+
+// ----------------------------------------------------------------------------
+//  Private Functions
+// ----------------------------------------------------------------------------
+
+// It sets the manifests to read for --check option. It is stdin if none given.
+// It returns an error if the options for a single input are used with it.
+func chkOptCheck(args []string) error {
+	switch {
+	case inStr != "":
+		return errors.New("--string option can not be used with --check option")
+	case inVerify != "":
+		return errors.New("--verify option can not be used with --check option")
+	case len(inSchemes) > 0:
+		return errors.New("--scheme option can not be used with --check option")
+	}
+
+	pathFiles = args
+	if len(pathFiles) == 0 {
+		pathFiles = []string{"-"}
+	}
+
+	return nil
+}
+
+// It reads the manifests of pathFiles and verifies the rawid of each file listed
+// in it. The lines of the manifest are "<rawid>  <path>", the same as the output
+// of multiple files. The rawid can be any representation. See parseRawidAny.
+//
+// It prints "<path>: OK", "<path>: FAILED" or "<path>: MISSING" for each file,
+// like sha256sum. It returns an error if any of them is not OK.
+//
+// The files are computed concurrently with the workers of --jobs option, while
+// the results are printed in the order of the manifests. See runOrdered.
+func runCheck() error {
+	var stats checkStats
+
+	_ = runOrdered(func(submit func(task)) error {
+		for _, pathManifest := range pathFiles {
+			checkManifest(pathManifest, &stats, submit)
+		}
+
+		return nil
+	})
+
+	printCheckWarning(stats.numInvalid, "line is", "lines are", "improperly formatted")
+	printCheckWarning(stats.numMissing, "listed file", "listed files", "could not be read")
+	printCheckWarning(stats.numFailed, "computed rawid", "computed rawids", "did NOT match")
+
+	return stats.err()
+}
+
+// It submits the tasks to verify the files listed in the manifest. Their outputs
+// count the results to stats. The manifest that can not be read is counted as
+// MISSING as well, so that the rest of the manifests are checked.
+func checkManifest(pathManifest string, stats *checkStats, submit func(task)) {
+	manifest := io.Reader(genrawid.OsStdin)
+
+	if pathManifest != "-" {
+		file, err := os.Open(pathManifest)
+		if err != nil {
+			err = errors.Wrap(hasher.NewErrRead(err), "failed to open manifest")
+
+			submit(func() func() {
+				return func() { stats.count(pathManifest, err) }
+			})
+
+			return
+		}
+
+		defer file.Close()
+
+		manifest = file
+	}
+
+	scanner := bufio.NewScanner(manifest)
+
+	for numLine := 1; scanner.Scan(); numLine++ {
+		line := strings.TrimSuffix(scanner.Text(), "\r")
+
+		// Skip empty lines and comments
+		if strings.TrimSpace(line) == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		numLine := numLine
+
+		expect, path, err := parseManifestLine(line)
+		if err != nil {
+			submit(func() func() {
+				return func() {
+					stats.numInvalid++
+
+					printCheckError("%s:%d: %v\n", pathManifest, numLine, err)
+				}
+			})
+
+			continue
+		}
+
+		submit(func() func() {
+			err := checkFile(path, expect)
+
+			return func() { stats.count(path, err) }
+		})
+	}
+
+	if err := scanner.Err(); err != nil {
+		err = errors.Wrap(hasher.NewErrRead(err), "failed to read manifest")
+
+		submit(func() func() {
+			return func() { stats.count(pathManifest, err) }
+		})
+	}
+}
+
+// It returns the rawid and the path of the line of manifest. The rawid can be in
+// any of the representations, the same as --verify option. See parseRawidAny.
+func parseManifestLine(line string) (rawidAny, string, error) {
+	posSep := strings.Index(line, "  ")
+	if posSep < 1 {
+		return nil, "", errors.New("improperly formatted line. it must be \"<rawid>  <path>\"")
+	}
+
+	expect, err := parseRawidAny(line[:posSep])
+	if err != nil {
+		return nil, "", errors.Wrap(err, "invalid rawid")
+	}
+
+	return expect, line[posSep+2:], nil
+}
+
+// It returns nil if the rawid of the file matches to expect.
+func checkFile(path string, expect rawidAny) error {
+	result, err := computeFile(path)
+	if err != nil {
+		return err
+	}
+
+	if !expect.matches(result.ID) {
+		return errors.Errorf("rawid did not match. expect: %s, actual: %s",
+			formatRawid(expect[0]), formatRawid(result.ID))
+	}
+
+	return nil
+}
+
+// It returns checkError if any of the files is not OK. The mismatch is prior to
+// the others to decide the exit status.
+func (s *checkStats) err() error {
+	switch {
+	case s.numFailed > 0:
+		return &checkError{stats: *s, kind: errMismatch}
+	case s.numMissing > 0:
+		return &checkError{stats: *s, kind: hasher.ErrRead}
+	case s.numInvalid > 0:
+		return &checkError{stats: *s, kind: &rawid.ErrDecode{}}
+	}
+
+	return nil
+}
+
+// It prints the result of the file and counts it.
+func (s *checkStats) count(path string, err error) {
+	switch {
+	case err == nil:
+		if !isQuiet && !isStatus {
+			//nolint:forbidigo // allow printing to stdout
+			fmt.Printf("%s: OK\n", path)
+		}
+	case errors.Is(err, hasher.ErrRead):
+		s.numMissing++
+
+		printCheckError("%s: %v\n", path, err)
+		printCheckResult("%s: MISSING\n", path)
+	default:
+		s.numFailed++
+
+		printCheckResult("%s: FAILED\n", path)
+	}
+}
+
+// It prints the result of a file to stdout unless --status option is set.
+func printCheckResult(format string, args ...interface{}) {
+	if !isStatus {
+		//nolint:forbidigo // allow printing to stdout
+		fmt.Printf(format, args...)
+	}
+}
+
+// It prints the error of a file or a line to stderr unless --status option is
+// set.
+func printCheckError(format string, args ...interface{}) {
+	if !isStatus {
+		fmt.Fprintf(os.Stderr, format, args...)
+	}
+}
+
+// It prints the summary of the count to stderr if any, like sha256sum.
+func printCheckWarning(count int, singular, plural, message string) {
+	if count == 0 {
+		return
+	}
+
+	subject := singular
+	if count > 1 {
+		subject = plural
+	}
+
+	printCheckError("WARNING: %d %s %s\n", count, subject, message)
+}
+
+// ----------------------------------------------------------------------------
+//  Type: checkError
+// ----------------------------------------------------------------------------
+
+// checkError is the error of runCheck. The results are already printed as the
+// warnings, so ExitOnError does not print it. It unwraps to the kind of the
+// error to decide the exit status.
+type checkError struct {
+	kind  error
+	stats checkStats
+}
+
+// Error implements the error interface.
+func (e *checkError) Error() string {
+	return fmt.Sprintf("verification failed. did not match: %d, could not be read: %d, improperly formatted: %d",
+		e.stats.numFailed, e.stats.numMissing, e.stats.numInvalid)
+}
+
+// Unwrap returns the kind of the error.
+func (e *checkError) Unwrap() error {
+	return e.kind
+}
@@ -0,0 +1,384 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/pflag"
+)
+
+// Names of the subcommands to generate the completion scripts and the man page.
+const (
+	cmdCompletion = "completion"
+	cmdMan        = "man"
+)
+
+// Names of the shells of the completion subcommand.
+const (
+	shellBash = "bash"
+	shellFish = "fish"
+	shellZsh  = "zsh"
+)
+
+// commands is the subcommands and their descriptions for the completions and
+// the man page.
+var commands = []struct {
+	name string
+	desc string
+}{
+	{cmdConvert, "converts the representations of rawids, such as from the decimal to Base62"},
+	{cmdConfig, "\"config show\" prints the effective settings and their sources"},
+	{cmdWatch, "keeps the rawids of the directories up to date. prints the changes as NDJSON or rewrites a manifest"},
+	{cmdDupes, "finds the duplicate files by the size, the rawid and the full digest. optionally replaces them with hard links"},
+	{cmdAudit, "reports the rawids shared by different contents and the observed versus expected collisions of a corpus"},
+	{cmdCompletion, "prints the completion script of the shell (bash, zsh, fish)"},
+	{cmdMan, "prints the man page in roff format"},
+}
+
+// flagsCommand is a subcommand with its own flags.
+type flagsCommand struct {
+	flags   *pflag.FlagSet
+	name    string
+	isFiles bool // the args are the file paths if true. Otherwise the rawids.
+}
+
+// ----------------------------------------------------------------------------
+//  Private Functions
+// ----------------------------------------------------------------------------
+
+// It runs the completion subcommand with the args after "completion". It prints
+// the completion script of the shell generated from the flag definitions.
+func runCompletion(args []string) error {
+	if len(args) != 1 {
+		return errors.New("usage: genrawid completion bash|zsh|fish")
+	}
+
+	setFlags()
+
+	// The watch, dupes and audit subcommands take the main flags as well
+	flagsWatch := newFlagsWatch(new(time.Duration), new(string))
+	flagsWatch.AddFlagSet(flagsWalkCommand())
+
+	flagsDupes := newFlagsDupes(new(bool), new(bool))
+	flagsDupes.AddFlagSet(flagsWalkCommand())
+
+	flagsAudit := newFlagsAudit(new(bool))
+	flagsAudit.AddFlagSet(flagsWalkCommand())
+
+	subcommands := []flagsCommand{
+		{name: cmdConvert, flags: newFlagsConvert(new(string), new(string))},
+		{name: cmdWatch, flags: flagsWatch, isFiles: true},
+		{name: cmdDupes, flags: flagsDupes, isFiles: true},
+		{name: cmdAudit, flags: flagsAudit, isFiles: true},
+	}
+
+	switch args[0] {
+	case shellBash:
+		writeBash(os.Stdout, pflag.CommandLine, subcommands)
+	case shellZsh:
+		writeZsh(os.Stdout, pflag.CommandLine, subcommands)
+	case shellFish:
+		writeFish(os.Stdout, pflag.CommandLine, subcommands)
+	default:
+		return errors.Errorf("unsupported shell: %s. it must be bash, zsh or fish", args[0])
+	}
+
+	return nil
+}
+
+// It returns the candidates of the value of the flag to complete and true if
+// the value is a file path.
+func valuesFlag(name string) ([]string, bool) {
+	switch name {
+	case "hash":
+		return namesHashAlgo(), false
+	case "checksum":
+		return namesChkSumAlgo(), false
+	case "crc-poly":
+		return namesCRCPoly(), false
+	case "format":
+		return []string{formatJSON, formatNDJSON, formatCSV, formatTSV}, false
+	case "fields":
+		return fieldsAll, false
+	case "from":
+		return []string{reprAuto, reprDec, reprUDec, reprHex, reprBase62}, false
+	case "to":
+		return []string{reprDec, reprUDec, reprHex, reprBase62, reprInt64}, false
+	case "config", "ignore-file", "manifest":
+		return nil, true
+	}
+
+	return nil, false
+}
+
+// It returns the main flags that the subcommands walking the directories take.
+// The ones of flagsWalkRejected are left out.
+func flagsWalkCommand() *pflag.FlagSet {
+	flags := pflag.NewFlagSet("walk", pflag.ContinueOnError)
+
+	pflag.CommandLine.VisitAll(func(flag *pflag.Flag) {
+		if !contains(flagsWalkRejected, flag.Name) {
+			flags.AddFlag(flag)
+		}
+	})
+
+	return flags
+}
+
+// It returns true if the flag takes no value, such as --hex.
+func isFlagBool(flag *pflag.Flag) bool {
+	return flag.Value.Type() == "bool"
+}
+
+// It returns the names of the subcommands.
+func namesCommand() []string {
+	names := make([]string, len(commands))
+
+	for i, command := range commands {
+		names[i] = command.name
+	}
+
+	return names
+}
+
+// ----------------------------------------------------------------------------
+//  Bash
+// ----------------------------------------------------------------------------
+
+func writeBash(out io.Writer, flags *pflag.FlagSet, subcommands []flagsCommand) {
+	fmt.Fprint(out, `# bash completion for genrawid. Generated by "genrawid completion bash".
+
+_genrawid() {
+    local cur="${COMP_WORDS[COMP_CWORD]}" prev="${COMP_WORDS[COMP_CWORD-1]}"
+
+    case "${COMP_WORDS[1]}" in
+`)
+
+	for _, subcommand := range subcommands {
+		fmt.Fprintf(out, "    %s)\n", subcommand.name)
+		writeBashFlags(out, subcommand.flags, "        ")
+
+		if subcommand.isFiles {
+			fmt.Fprint(out, "        COMPREPLY=($(compgen -f -- \"$cur\"))\n")
+		}
+
+		fmt.Fprint(out, "        return ;;\n")
+	}
+
+	fmt.Fprintf(out, `    %s)
+        [[ $COMP_CWORD -eq 2 ]] && COMPREPLY=($(compgen -W "%s %s %s" -- "$cur"))
+        return ;;
+    %s)
+        return ;;
+    %s)
+        if [[ $COMP_CWORD -eq 2 ]]; then
+            COMPREPLY=($(compgen -W "show" -- "$cur"))
+            return
+        fi ;;
+    esac
+
+`, cmdCompletion, shellBash, shellZsh, shellFish, cmdMan, cmdConfig)
+	writeBashFlags(out, flags, "    ")
+	fmt.Fprintf(out, `
+    [[ $COMP_CWORD -eq 1 ]] && COMPREPLY=($(compgen -W "%s" -- "$cur"))
+    COMPREPLY+=($(compgen -f -- "$cur"))
+}
+
+complete -o filenames -F _genrawid genrawid
+`, strings.Join(namesCommand(), " "))
+}
+
+// It writes the completions of the values and the names of the flags.
+func writeBashFlags(out io.Writer, flags *pflag.FlagSet, indent string) {
+	var namesAll, namesNoCandidate []string
+
+	fmt.Fprintf(out, "%scase \"$prev\" in\n", indent)
+
+	flags.VisitAll(func(flag *pflag.Flag) {
+		names := []string{"--" + flag.Name}
+		if flag.Shorthand != "" {
+			names = append(names, "-"+flag.Shorthand)
+		}
+
+		namesAll = append(namesAll, names...)
+
+		if isFlagBool(flag) {
+			return
+		}
+
+		switch values, isFile := valuesFlag(flag.Name); {
+		case isFile:
+			fmt.Fprintf(out, "%s%s) COMPREPLY=($(compgen -f -- \"$cur\")); return ;;\n",
+				indent, strings.Join(names, "|"))
+		case len(values) > 0:
+			fmt.Fprintf(out, "%s%s) COMPREPLY=($(compgen -W \"%s\" -- \"$cur\")); return ;;\n",
+				indent, strings.Join(names, "|"), strings.Join(values, " "))
+		default:
+			namesNoCandidate = append(namesNoCandidate, names...)
+		}
+	})
+
+	if len(namesNoCandidate) > 0 {
+		fmt.Fprintf(out, "%s%s) return ;;\n", indent, strings.Join(namesNoCandidate, "|"))
+	}
+
+	fmt.Fprintf(out, "%sesac\n\n", indent)
+	fmt.Fprintf(out, "%sif [[ \"$cur\" == -* ]]; then\n", indent)
+	fmt.Fprintf(out, "%s    COMPREPLY=($(compgen -W \"%s\" -- \"$cur\"))\n", indent, strings.Join(namesAll, " "))
+	fmt.Fprintf(out, "%s    return\n%sfi\n", indent, indent)
+}
+
+// ----------------------------------------------------------------------------
+//  Zsh
+// ----------------------------------------------------------------------------
+
+func writeZsh(out io.Writer, flags *pflag.FlagSet, subcommands []flagsCommand) {
+	fmt.Fprintf(out, `#compdef genrawid
+
+# zsh completion for genrawid. Generated by "genrawid completion zsh".
+
+_genrawid() {
+  if (( CURRENT == 2 )) && [[ $words[2] != -* ]]; then
+    _alternative 'commands:command:(%s)' 'files:file:_files'
+    return
+  fi
+
+  case $words[2] in
+`, strings.Join(namesCommand(), " "))
+
+	for _, subcommand := range subcommands {
+		fmt.Fprintf(out, "    %s)\n      _arguments -s \\\n", subcommand.name)
+		writeZshSpecs(out, subcommand.flags, "        ")
+
+		if subcommand.isFiles {
+			fmt.Fprint(out, "        '*:file:_files'\n      return ;;\n")
+		} else {
+			fmt.Fprint(out, "        '*:rawid: '\n      return ;;\n")
+		}
+	}
+
+	fmt.Fprintf(out, `    %s)
+      (( CURRENT == 3 )) && compadd %s %s %s
+      return ;;
+    %s)
+      return ;;
+    %s)
+      if (( CURRENT == 3 )); then
+        compadd show
+        return
+      fi ;;
+  esac
+
+  _arguments -s \
+`, cmdCompletion, shellBash, shellZsh, shellFish, cmdMan, cmdConfig)
+	writeZshSpecs(out, flags, "    ")
+	fmt.Fprint(out, `    '*:file:_files'
+}
+
+compdef _genrawid genrawid
+`)
+}
+
+// It writes the specs of _arguments for the flags. Such as:
+//
+//	'(-c --check)'{-c,--check}'[description]'
+//	'--hash=[description]:hash:(blake3 sha3-512 xxh64)'
+func writeZshSpecs(out io.Writer, flags *pflag.FlagSet, indent string) {
+	flags.VisitAll(func(flag *pflag.Flag) {
+		desc := "[" + escapeZsh(flag.Usage) + "]"
+
+		if !isFlagBool(flag) {
+			desc = "=" + desc + ":" + flag.Name + ":"
+
+			switch values, isFile := valuesFlag(flag.Name); {
+			case isFile:
+				desc += "_files"
+			case len(values) > 0:
+				desc += "(" + strings.Join(values, " ") + ")"
+			default:
+				desc += " "
+			}
+		}
+
+		// The flags that can be given more than once, such as --exclude
+		repeat := ""
+		if strings.HasSuffix(flag.Value.Type(), "Array") {
+			repeat = "*"
+		}
+
+		if flag.Shorthand == "" {
+			fmt.Fprintf(out, "%s'%s--%s%s' \\\n", indent, repeat, flag.Name, desc)
+
+			return
+		}
+
+		fmt.Fprintf(out, "%s'(-%s --%s)'{-%s,--%s}'%s' \\\n",
+			indent, flag.Shorthand, flag.Name, flag.Shorthand, flag.Name, desc)
+	})
+}
+
+// It escapes the description in the brackets of the single-quoted spec.
+func escapeZsh(desc string) string {
+	return strings.NewReplacer(
+		`\`, `\\`,
+		`[`, `\[`,
+		`]`, `\]`,
+		`'`, `'\''`,
+	).Replace(desc)
+}
+
+// ----------------------------------------------------------------------------
+//  Fish
+// ----------------------------------------------------------------------------
+
+func writeFish(out io.Writer, flags *pflag.FlagSet, subcommands []flagsCommand) {
+	fmt.Fprint(out, "# fish completion for genrawid. Generated by \"genrawid completion fish\".\n\n")
+
+	for _, command := range commands {
+		fmt.Fprintf(out, "complete -c genrawid -n '__fish_use_subcommand' -a %s -d '%s'\n",
+			command.name, escapeFish(command.desc))
+	}
+
+	fmt.Fprintf(out, "complete -c genrawid -n '__fish_seen_subcommand_from %s' -f -a '%s %s %s'\n",
+		cmdCompletion, shellBash, shellZsh, shellFish)
+	fmt.Fprintf(out, "complete -c genrawid -n '__fish_seen_subcommand_from %s' -f -a 'show'\n", cmdConfig)
+
+	for _, subcommand := range subcommands {
+		writeFishFlags(out, subcommand.flags, "__fish_seen_subcommand_from "+subcommand.name)
+	}
+
+	writeFishFlags(out, flags, "not __fish_seen_subcommand_from "+strings.Join(namesCommand(), " "))
+}
+
+// It writes the completions of the flags on the condition.
+func writeFishFlags(out io.Writer, flags *pflag.FlagSet, condition string) {
+	flags.VisitAll(func(flag *pflag.Flag) {
+		line := fmt.Sprintf("complete -c genrawid -n '%s' -l %s", condition, flag.Name)
+
+		if flag.Shorthand != "" {
+			line += " -s " + flag.Shorthand
+		}
+
+		if !isFlagBool(flag) {
+			switch values, isFile := valuesFlag(flag.Name); {
+			case isFile:
+				line += " -r -F"
+			case len(values) > 0:
+				line += " -x -a '" + strings.Join(values, " ") + "'"
+			default:
+				line += " -x"
+			}
+		}
+
+		fmt.Fprintf(out, "%s -d '%s'\n", line, escapeFish(flag.Usage))
+	})
+}
+
+// It escapes the description in the single quotes.
+func escapeFish(desc string) string {
+	return strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(desc)
+}
@@ -0,0 +1,340 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/KEINOS/go-genrawid/pkg/hasher"
+	"github.com/pkg/errors"
+	"github.com/spf13/pflag"
+)
+
+// cmdConfig is the name of the subcommand to show the settings.
+const cmdConfig = "config"
+
+// envPrefix is the prefix of the environment variables of the settings. Such as
+// GENRAWID_HASH for --hash option and GENRAWID_CONFIG for --config option.
+const envPrefix = "GENRAWID_"
+
+// keysConfig is the names of the flags that can be set in the config file and
+// the environment variables. They are the settings of the rawids and the
+// outputs, not of the inputs.
+var keysConfig = []string{
+	"base62",
+	"checksum",
+	"concurrency",
+	"context",
+	"crc-poly",
+	"fast",
+	"hash",
+	"hash-len",
+	"hex",
+	"jobs",
+	"new-line",
+}
+
+// groupsConfig is the keys of keysConfig that affect each other. Such as --hex
+// option is prior to --base62, and --fast option can not be used with the
+// algorithm options. The precedence applies to each group as a whole. Only the
+// keys of a group set in the highest source are taken, so that they are not
+// overridden nor conflicted by the rest of the group in the lower sources.
+var groupsConfig = [][]string{
+	{"base62", "hex"},
+	{"fast", "hash", "hash-len", "checksum", "crc-poly"},
+}
+
+// Sources of the settings in the order of the precedence. See loadConfig.
+const (
+	srcDefault = iota
+	srcFile
+	srcEnv
+	srcFlag
+)
+
+// namesSrcConfig is the names of the sources to report the settings ignored.
+var namesSrcConfig = map[int]string{
+	srcFile: "the config file",
+	srcEnv:  "the environment variables",
+	srcFlag: "the flags",
+}
+
+// srcConfig holds the source of each setting of keysConfig. Such as "flag",
+// "env GENRAWID_HASH", "file /path/to/config" or "default". See loadConfig.
+var srcConfig map[string]string
+
+// ----------------------------------------------------------------------------
+//  Private Functions
+// ----------------------------------------------------------------------------
+
+// It sets the flags of keysConfig that are not given on the command line from
+// the environment variables and the config file. The precedence is:
+//
+//	flags > environment variables > config file > built-in defaults
+//
+// The config file is the one of --config option, GENRAWID_CONFIG or
+// "genrawid/config" in the user config dir, such as $XDG_CONFIG_HOME. The last
+// one is optional.
+//
+// The keys of a group of groupsConfig are set only from the highest source of
+// the group. Such as, if GENRAWID_HASH is set, "fast = true" in the config file
+// is ignored rather than conflicts with it.
+func loadConfig() error {
+	pathConfig, isExplicit := pathConfigFile()
+
+	settings, err := readConfigFile(pathConfig, isExplicit)
+	if err != nil {
+		return err
+	}
+
+	srcConfig = make(map[string]string, len(keysConfig))
+
+	for _, key := range keysConfig {
+		value, src := lookupConfig(key, settings)
+
+		source := ""
+
+		switch src {
+		case srcEnv:
+			source = "env " + nameEnv(key)
+		case srcFile:
+			source = "file " + pathConfig
+		}
+
+		switch srcGroup := srcGroupConfig(key, settings); {
+		case src == srcFlag:
+			srcConfig[key] = "flag"
+		case src == srcDefault:
+			srcConfig[key] = "default"
+		case src < srcGroup:
+			srcConfig[key] = "default (" + source + " is ignored for " + namesSrcConfig[srcGroup] + ")"
+		default:
+			// Set via Value so that it is not taken as given
+			if err := pflag.Lookup(key).Value.Set(value); err != nil {
+				return errors.Wrapf(err, "invalid %s of %s", key, source)
+			}
+
+			srcConfig[key] = source
+		}
+	}
+
+	return nil
+}
+
+// It returns the value of the key and its highest source. The value is empty if
+// the source is the flag or the default.
+func lookupConfig(key string, settings map[string]string) (string, int) {
+	if pflag.Lookup(key).Changed {
+		return "", srcFlag
+	}
+
+	if valueEnv, ok := os.LookupEnv(nameEnv(key)); ok && valueEnv != "" {
+		return valueEnv, srcEnv
+	}
+
+	if valueFile, ok := settings[key]; ok {
+		return valueFile, srcFile
+	}
+
+	return "", srcDefault
+}
+
+// It returns the highest source of the keys of the group of the key in
+// groupsConfig. It is the source of the key itself if it is of no group.
+func srcGroupConfig(key string, settings map[string]string) int {
+	_, srcMax := lookupConfig(key, settings)
+
+	for _, group := range groupsConfig {
+		if !contains(group, key) {
+			continue
+		}
+
+		for _, keyGroup := range group {
+			if _, src := lookupConfig(keyGroup, settings); src > srcMax {
+				srcMax = src
+			}
+		}
+	}
+
+	return srcMax
+}
+
+// It returns the path of the config file and true if it is given via --config
+// option or GENRAWID_CONFIG. It is empty if the user config dir is unknown.
+func pathConfigFile() (string, bool) {
+	if inConfig != "" {
+		return inConfig, true
+	}
+
+	if pathEnv := os.Getenv(nameEnv("config")); pathEnv != "" {
+		return pathEnv, true
+	}
+
+	dirConfig, err := os.UserConfigDir()
+	if err != nil {
+		return "", false
+	}
+
+	return filepath.Join(dirConfig, "genrawid", "config"), false
+}
+
+// It returns the settings in the config file. The lines are "<key> = <value>"
+// where the keys are the flag names of keysConfig. Such as:
+//
+//	# Team defaults
+//	hash = sha3-512
+//	crc-poly = koopman
+//	base62 = true
+//	context = "acme #1" # quoted values may have "#"
+//
+// Empty lines and the lines beginning with "#" are skipped. It is not an error
+// if the file is not explicitly given and does not exist.
+func readConfigFile(pathConfig string, isExplicit bool) (map[string]string, error) {
+	if pathConfig == "" {
+		return nil, nil
+	}
+
+	file, err := os.Open(pathConfig)
+	if err != nil {
+		if !isExplicit && os.IsNotExist(err) {
+			return nil, nil
+		}
+
+		return nil, errors.Wrap(hasher.NewErrRead(err), "failed to open config file")
+	}
+
+	defer file.Close()
+
+	settings := map[string]string{}
+	scanner := bufio.NewScanner(file)
+
+	for numLine := 1; scanner.Scan(); numLine++ {
+		line := strings.TrimSpace(scanner.Text())
+
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		key, value, err := parseConfigLine(line)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid config file %s:%d", pathConfig, numLine)
+		}
+
+		settings[key] = value
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, errors.Wrap(hasher.NewErrRead(err), "failed to read config file")
+	}
+
+	return settings, nil
+}
+
+// It returns the key and the value of the line of the config file. The value may
+// be followed by a comment beginning with " #". To have "#" in the value, quote
+// it with double quotes.
+func parseConfigLine(line string) (string, string, error) {
+	const numParts = 2
+
+	parts := strings.SplitN(line, "=", numParts)
+	if len(parts) != numParts {
+		return "", "", errors.New("the line must be \"<key> = <value>\"")
+	}
+
+	key, value := strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1])
+
+	if !isKeyConfig(key) {
+		return "", "", errors.Errorf("unknown key: %s. it must be one of %s", key, strings.Join(keysConfig, ", "))
+	}
+
+	if strings.HasPrefix(value, `"`) {
+		posEnd := strings.LastIndex(value, `"`)
+
+		unquoted, err := strconv.Unquote(value[:posEnd+1])
+		if err != nil || !isComment(value[posEnd+1:]) {
+			return "", "", errors.Errorf("invalid quoted value of %s: %s", key, value)
+		}
+
+		return key, unquoted, nil
+	}
+
+	if posComment := strings.Index(value, " #"); posComment >= 0 {
+		value = strings.TrimSpace(value[:posComment])
+	}
+
+	return key, value, nil
+}
+
+// It returns true if the rest of the line is empty or a comment.
+func isComment(rest string) bool {
+	rest = strings.TrimSpace(rest)
+
+	return rest == "" || strings.HasPrefix(rest, "#")
+}
+
+func contains(list []string, item string) bool {
+	for _, elem := range list {
+		if elem == item {
+			return true
+		}
+	}
+
+	return false
+}
+
+func isKeyConfig(key string) bool {
+	return contains(keysConfig, key)
+}
+
+// It returns the name of the environment variable of the flag. Such as
+// GENRAWID_CRC_POLY for --crc-poly option.
+func nameEnv(key string) string {
+	return envPrefix + strings.ToUpper(strings.ReplaceAll(key, "-", "_"))
+}
+
+// It runs the config subcommand with the args after "config". Only "show" is
+// available, which prints the effective settings with their sources in the
+// format of the config file. The flags after it are taken into account as well.
+func runConfig(args []string) error {
+	if len(args) == 0 || args[0] != "show" {
+		return errors.New("unknown config command. usage: genrawid config show [flags]")
+	}
+
+	setFlags()
+
+	if err := pflag.CommandLine.Parse(args[1:]); err != nil {
+		return errors.Wrap(err, "invalid flags")
+	}
+
+	if err := loadConfig(); err != nil {
+		return err
+	}
+
+	pathConfig, _ := pathConfigFile()
+	if _, err := os.Stat(pathConfig); err != nil {
+		pathConfig += " (not found)"
+	}
+
+	//nolint:forbidigo // allow printing to stdout
+	fmt.Printf("# Precedence: flag > env > file > default\n# Config file: %s\n", pathConfig)
+
+	for _, key := range keysConfig {
+		//nolint:forbidigo // allow printing to stdout
+		fmt.Printf("%s = %s # %s\n", key, quoteConfig(pflag.Lookup(key).Value.String()), srcConfig[key])
+	}
+
+	return nil
+}
+
+// It returns the value quoted if it is empty or may not be read as is from the
+// config file.
+func quoteConfig(value string) string {
+	if value == "" || strings.ContainsAny(value, `#"`) || strings.TrimSpace(value) != value {
+		return strconv.Quote(value)
+	}
+
+	return value
+}
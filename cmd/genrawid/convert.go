@@ -0,0 +1,258 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/KEINOS/go-genrawid"
+	"github.com/KEINOS/go-genrawid/pkg/hasher"
+	"github.com/KEINOS/go-genrawid/pkg/rawid"
+	"github.com/KEINOS/go-utiles/util"
+	"github.com/pkg/errors"
+	"github.com/spf13/pflag"
+)
+
+// cmdConvert is the name of the subcommand to convert the representations of
+// rawids.
+const cmdConvert = "convert"
+
+// markNegative is the prefix to escape the negative decimals from pflag. See
+// escapeNegatives.
+const markNegative = "\x00"
+
+// Names of the representations of the convert subcommand.
+const (
+	reprAuto   = "auto"
+	reprBase62 = "base62"
+	reprDec    = "dec"
+	reprHex    = "hex"
+	reprInt64  = "int64"
+	reprUDec   = "udec"
+)
+
+// ----------------------------------------------------------------------------
+//  Private Functions
+// ----------------------------------------------------------------------------
+
+// It runs the convert subcommand with the args after "convert". It converts
+// the rawids given as the args, or each line of stdin if none given, to the
+// representation of --to option and prints one per line.
+//
+// The invalid ones are reported to stderr and printed as empty lines, so that
+// the outputs stay aligned with the inputs. Then it returns the error of the
+// first one at the end.
+func runConvert(args []string) error {
+	var inFrom, inTo string
+
+	flags := newFlagsConvert(&inFrom, &inTo)
+	flags.Usage = func() { usageConvert(flags) }
+
+	if err := flags.Parse(escapeNegatives(args)); err != nil {
+		if errors.Is(err, pflag.ErrHelp) {
+			return nil
+		}
+
+		return errors.Wrap(err, "invalid arguments of convert")
+	}
+
+	parse, err := parserFrom(inFrom)
+	if err != nil {
+		return err
+	}
+
+	format, err := formatterTo(inTo)
+	if err != nil {
+		return err
+	}
+
+	convert := func(value string) (string, error) {
+		//nolint:varnamelen // allow short variable names for readability
+		id, err := parse(value)
+		if err != nil {
+			return "", errors.Wrap(err, "invalid rawid")
+		}
+
+		return format(id), nil
+	}
+
+	if flags.NArg() > 0 {
+		return convertValues(unescapeNegatives(flags.Args()), "arg", convert)
+	}
+
+	return convertLines(genrawid.OsStdin, convert)
+}
+
+// It returns the flags of the convert subcommand. It is used for the completions
+// and the man page as well.
+func newFlagsConvert(inFrom, inTo *string) *pflag.FlagSet {
+	flags := pflag.NewFlagSet(cmdConvert, pflag.ContinueOnError)
+
+	flags.StringVar(inFrom, "from", reprAuto, "representation of the input (auto, dec, udec, hex, base62)")
+	flags.StringVar(inTo, "to", "", "representation to convert to (dec, udec, hex, base62, int64)")
+
+	return flags
+}
+
+// It escapes the negative decimals in args, such as "-2474118025671277174", so
+// that pflag does not take them as the shorthand flags. Which saves the users
+// from "--" before them.
+func escapeNegatives(args []string) []string {
+	escaped := make([]string, len(args))
+
+	for i, arg := range args {
+		escaped[i] = arg
+
+		if len(arg) > 1 && arg[0] == '-' && strings.Trim(arg[1:], "0123456789") == "" {
+			escaped[i] = markNegative + arg
+		}
+	}
+
+	return escaped
+}
+
+// It reverts escapeNegatives.
+func unescapeNegatives(args []string) []string {
+	unescaped := make([]string, len(args))
+
+	for i, arg := range args {
+		unescaped[i] = strings.TrimPrefix(arg, markNegative)
+	}
+
+	return unescaped
+}
+
+// It returns the parser of the representation of --from option.
+func parserFrom(name string) (func(string) (rawid.ID, error), error) {
+	switch name {
+	case reprAuto:
+		return rawid.Parse, nil
+	case reprDec, reprInt64:
+		return rawid.NewDec, nil
+	case reprUDec:
+		return rawid.NewUDec, nil
+	case reprHex:
+		return rawid.NewHex, nil
+	case reprBase62:
+		return rawid.NewBase62, nil
+	}
+
+	return nil, errors.Errorf("invalid --from option: %s. it must be auto, dec, udec, hex or base62", name)
+}
+
+// It returns the function to format a rawid in the representation of --to
+// option. The hex is with "0x" prefix the same as --hex option, so that it is
+// auto-detected on the way back.
+func formatterTo(name string) (func(rawid.ID) string, error) {
+	switch name {
+	case reprDec:
+		return rawid.ID.Dec, nil
+	case reprInt64:
+		return func(id rawid.ID) string { return strconv.FormatInt(id.Int64(), 10) }, nil
+	case reprUDec:
+		return rawid.ID.UDec, nil
+	case reprHex:
+		return func(id rawid.ID) string { return "0x" + id.Hex() }, nil
+	case reprBase62:
+		return rawid.ID.Base62, nil
+	case "":
+		return nil, errors.New("missing --to option. it must be dec, udec, hex, base62 or int64")
+	}
+
+	return nil, errors.Errorf("invalid --to option: %s. it must be dec, udec, hex, base62 or int64", name)
+}
+
+// It converts the values and prints them. The empty ones are printed as is. The
+// errors are reported with the unit and the number of the value, such as "line 2".
+func convertValues(values []string, unit string, convert func(string) (string, error)) error {
+	var (
+		errFirst  error
+		numFailed int
+	)
+
+	for i, value := range values {
+		value = strings.TrimSpace(value)
+		if value == "" {
+			//nolint:forbidigo // allow printing to stdout
+			fmt.Println()
+
+			continue
+		}
+
+		converted, err := convert(value)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s %d: %v\n", unit, i+1, err)
+
+			if errFirst == nil {
+				errFirst = err
+			}
+
+			numFailed++
+		}
+
+		//nolint:forbidigo // allow printing to stdout
+		fmt.Println(converted)
+	}
+
+	if errFirst != nil && len(values) > 1 {
+		return errors.Wrapf(errFirst, "failed to convert %d of %d values", numFailed, len(values))
+	}
+
+	return errFirst
+}
+
+// It converts each line of the input and prints them.
+func convertLines(input io.Reader, convert func(string) (string, error)) error {
+	var values []string
+
+	scanner := bufio.NewScanner(input)
+
+	for scanner.Scan() {
+		values = append(values, scanner.Text())
+	}
+
+	if err := scanner.Err(); err != nil {
+		return errors.Wrap(hasher.NewErrRead(err), "failed to read from STDIN")
+	}
+
+	return convertValues(values, "line", convert)
+}
+
+func usageConvert(flags *pflag.FlagSet) {
+	fmt.Fprintln(os.Stderr, util.HereDoc(`
+		genrawid convert - converts the representations of rawids.
+
+		Usage:
+		  genrawid convert --to <dec|udec|hex|base62|int64> [flags] [rawid]...
+
+		  The representation of the input is auto-detected. Such as "0x..." for
+		  hex, digits for decimal and Base62 otherwise. If no rawid is given, it
+		  converts each line of STDIN. The invalid ones are reported to STDERR
+		  and printed as empty lines to keep the outputs aligned.
+	`))
+
+	fmt.Fprintln(os.Stderr, "Flags:")
+	flags.PrintDefaults()
+
+	fmt.Fprintln(os.Stderr, util.HereDoc(`
+
+		Example:
+		  $ # From the signed decimal of SQLite to the Base62 of the URLs.
+		  $ genrawid convert --to base62 -2474118025671277174
+		  j1UNoJA6ku6
+
+		  $ # And back.
+		  $ genrawid convert --to dec j1UNoJA6ku6
+		  -2474118025671277174
+
+		  $ # Base62 strings of digits only are detected as decimal. Specify
+		  $ # the representation of the input for them.
+		  $ genrawid convert --from base62 --to hex 12345
+
+		  $ # Convert each line of the input.
+		  $ cat rawids.txt | genrawid convert --to hex
+	`))
+}
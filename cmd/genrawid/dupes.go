@@ -0,0 +1,436 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/KEINOS/go-genrawid"
+	"github.com/KEINOS/go-genrawid/pkg/hasher"
+	"github.com/KEINOS/go-utiles/util"
+	"github.com/pkg/errors"
+	"github.com/spf13/pflag"
+)
+
+// cmdDupes is the name of the subcommand to find the duplicate files.
+const cmdDupes = "dupes"
+
+// sizeCompareBuf is the size of the buffers to compare the files byte by byte.
+const sizeCompareBuf = 64 * 1024
+
+// ----------------------------------------------------------------------------
+//  Private Functions
+// ----------------------------------------------------------------------------
+
+// It runs the dupes subcommand with the args after "dupes". It finds the files
+// of the same content in the files and the directories given and prints the
+// groups of them.
+//
+// The files are grouped by the size first, and only the ones of the same size
+// are computed. Then by the rawid and the full digest, and confirmed byte by
+// byte. Since the digests of some hash algorithms ignore the line breaks, the
+// files of the same digest may differ.
+//
+// With --link option, the duplicates are replaced with the hard links to the
+// first file of each group. With --summary option, the reclaimable space is
+// reported to stderr.
+func runDupes(args []string) error {
+	var isLink, isSummary bool
+
+	setFlags()
+
+	pflag.CommandLine.AddFlagSet(newFlagsDupes(&isLink, &isSummary))
+
+	if err := pflag.CommandLine.Parse(args); err != nil {
+		return errors.Wrap(err, "invalid flags")
+	}
+
+	if err := loadConfig(); err != nil {
+		return err
+	}
+
+	if isHelp {
+		usageDupes()
+
+		return nil
+	}
+
+	paths, err := chkOptDupes(expandGlobs(pflag.Args()))
+	if err != nil {
+		return err
+	}
+
+	var (
+		errFiles                  filesError
+		errLink                   error
+		numDupes, sizeReclaimable int64
+		numLinkFailed             int
+	)
+
+	groups := findDupes(paths, &errFiles)
+
+	for i, group := range groups {
+		if i > 0 {
+			//nolint:forbidigo // allow printing to stdout
+			fmt.Println()
+		}
+
+		for j, dupe := range group {
+			//nolint:forbidigo // allow printing to stdout
+			fmt.Printf("%s  %s\n", formatRawid(dupe.result.ID), dupe.path)
+
+			if j == 0 {
+				continue
+			}
+
+			numDupes++
+			sizeReclaimable += dupe.result.Size
+
+			if !isLink {
+				continue
+			}
+
+			if err := linkDupe(group[0].path, dupe.path); err != nil {
+				fmt.Fprintf(os.Stderr, "%s: %v\n", dupe.path, err)
+
+				if errLink == nil {
+					errLink = err
+				}
+
+				numLinkFailed++
+			}
+		}
+	}
+
+	if isSummary {
+		verb := "reclaimable"
+		if isLink {
+			verb = "reclaimed"
+		}
+
+		fmt.Fprintf(os.Stderr, "%s, %s, %s (%d bytes) %s\n",
+			plural(int64(len(groups)), "group"), plural(numDupes, "duplicate file"),
+			formatBytes(float64(sizeReclaimable)), sizeReclaimable, verb)
+	}
+
+	if errFiles.first != nil {
+		return &errFiles
+	}
+
+	if errLink != nil {
+		return errors.Wrapf(errLink, "failed to link %d of %d duplicates", numLinkFailed, numDupes)
+	}
+
+	return nil
+}
+
+// It returns --link and --summary flags. See newFlagsWatch.
+func newFlagsDupes(isLink, isSummary *bool) *pflag.FlagSet {
+	flags := pflag.NewFlagSet(cmdDupes, pflag.ContinueOnError)
+
+	flags.BoolVar(isLink, "link", false, "replaces the duplicates with the hard links to the first file of each group")
+	flags.BoolVar(isSummary, "summary", false, "reports the number of the duplicates and the reclaimable space to STDERR")
+
+	return flags
+}
+
+// It returns the paths to find the duplicates in if the args and the options are
+// valid. See chkOptWalkCommand for the main flags.
+func chkOptDupes(args []string) ([]string, error) {
+	if len(args) == 0 {
+		return nil, errors.New("missing paths. usage: genrawid dupes [flags] <path>...")
+	}
+
+	for _, arg := range args {
+		if arg == "-" {
+			return nil, errors.New("STDIN can not be used with dupes")
+		}
+	}
+
+	if err := chkOptWalkCommand(cmdDupes); err != nil {
+		return nil, err
+	}
+
+	return args, nil
+}
+
+func usageDupes() {
+	fmt.Fprintln(os.Stderr, util.HereDoc(`
+		genrawid dupes - finds the duplicate files.
+
+		Usage:
+		  genrawid dupes [flags] <path>...
+
+		  It prints the groups of the files of the same content as the lines of
+		  "<rawid>  <path>", separated by empty lines. The directories are
+		  walked recursively. The empty files and the hard links of the same
+		  file are skipped.
+
+		  The files are grouped by the size first, then by the rawid and the
+		  full digest, and the groups are confirmed byte by byte.
+	`))
+
+	fmt.Fprintln(os.Stderr, "Flags:")
+	pflag.PrintDefaults()
+
+	fmt.Fprintln(os.Stderr, util.HereDoc(`
+
+		Example:
+		  $ genrawid dupes --summary ./photos ./backup
+		  -2474118025671277174  backup/a.jpg
+		  -2474118025671277174  photos/a.jpg
+
+		  1 group, 1 duplicate file, 2.3 MiB (2411724 bytes) reclaimable
+
+		  $ # Replace the duplicates with the hard links to the first ones
+		  $ genrawid dupes --link --exclude ".git/" ./photos ./backup
+	`))
+}
+
+// dupe is a file to find the duplicates of.
+type dupe struct {
+	info   os.FileInfo
+	path   string
+	result genrawid.Result
+}
+
+// It returns the groups of the duplicate files in the paths. The files in each
+// group are in the lexical order and so are the groups by the first file. The
+// errors of the files are counted to errFiles.
+func findDupes(paths []string, errFiles *filesError) [][]*dupe {
+	var (
+		bySize = map[int64][]*dupe{}
+		sizes  []int64
+	)
+
+	visit := func(path string, err error) {
+		var info os.FileInfo
+
+		if err == nil {
+			if info, err = os.Stat(path); err != nil {
+				err = errors.Wrap(hasher.NewErrRead(err), "failed to read from file")
+			}
+		}
+
+		if err != nil {
+			errFiles.count(path, err)
+
+			return
+		}
+
+		if !info.Mode().IsRegular() || info.Size() == 0 {
+			return
+		}
+
+		// The hard links of the same file are not duplicates to reclaim
+		for _, other := range bySize[info.Size()] {
+			if os.SameFile(other.info, info) {
+				return
+			}
+		}
+
+		if _, ok := bySize[info.Size()]; !ok {
+			sizes = append(sizes, info.Size())
+		}
+
+		bySize[info.Size()] = append(bySize[info.Size()], &dupe{info: info, path: path})
+	}
+
+	for _, path := range paths {
+		if isDir(path) {
+			walkDir(path, visit)
+
+			continue
+		}
+
+		visit(path, nil)
+	}
+
+	// Compute the files of the same size only
+	_ = runOrdered(func(submit func(task)) error {
+		for _, size := range sizes {
+			if len(bySize[size]) < 2 {
+				continue
+			}
+
+			for _, candidate := range bySize[size] {
+				candidate := candidate
+
+				submit(func() func() {
+					result, err := computeFile(candidate.path)
+
+					return func() {
+						candidate.result = result
+						errFiles.count(candidate.path, err)
+					}
+				})
+			}
+		}
+
+		return nil
+	})
+
+	var groups [][]*dupe
+
+	for _, size := range sizes {
+		for _, group := range groupDupes(bySize[size], errFiles) {
+			if len(group) < 2 {
+				continue
+			}
+
+			sort.Slice(group, func(i, j int) bool { return group[i].path < group[j].path })
+
+			groups = append(groups, group)
+		}
+	}
+
+	sort.Slice(groups, func(i, j int) bool { return groups[i][0].path < groups[j][0].path })
+
+	return groups
+}
+
+// It groups the files by the rawid and the full digest, then byte by byte. The
+// digest does not tell the contents apart by itself, since the line breaks are
+// ignored by BLAKE3 and SHA3-512 and it is the rawid itself in fast mode. The
+// files failed to compute are skipped.
+func groupDupes(candidates []*dupe, errFiles *filesError) [][]*dupe {
+	var (
+		byDigest = map[string][]*dupe{}
+		keys     []string
+	)
+
+	for _, candidate := range candidates {
+		if candidate.result.ID == nil {
+			continue
+		}
+
+		key := string(candidate.result.ID) + string(candidate.result.Digest)
+
+		if _, ok := byDigest[key]; !ok {
+			keys = append(keys, key)
+		}
+
+		byDigest[key] = append(byDigest[key], candidate)
+	}
+
+	groups := make([][]*dupe, 0, len(keys))
+
+	for _, key := range keys {
+		if len(byDigest[key]) < 2 {
+			groups = append(groups, byDigest[key])
+
+			continue
+		}
+
+		groups = append(groups, groupBytes(byDigest[key], errFiles)...)
+	}
+
+	return groups
+}
+
+// It groups the files by their content byte by byte. Each file is compared to
+// the first file of the groups so far, which is a single group unless the rawid
+// and the digest collided. The files are already counted to errFiles when they
+// were computed, so the ones failed to compare are only recorded as failed.
+func groupBytes(candidates []*dupe, errFiles *filesError) [][]*dupe {
+	var groups [][]*dupe
+
+next:
+	for _, candidate := range candidates {
+		for i, group := range groups {
+			isSame, err := isSameContent(group[0].path, candidate.path)
+			if err != nil {
+				errFiles.fail(candidate.path, err)
+
+				continue next
+			}
+
+			if isSame {
+				groups[i] = append(groups[i], candidate)
+
+				continue next
+			}
+		}
+
+		groups = append(groups, []*dupe{candidate})
+	}
+
+	return groups
+}
+
+// It returns true if the contents of the two files are the same.
+func isSameContent(pathA, pathB string) (bool, error) {
+	fileA, err := os.Open(pathA)
+	if err != nil {
+		return false, errors.Wrap(hasher.NewErrRead(err), "failed to compare")
+	}
+
+	defer fileA.Close()
+
+	fileB, err := os.Open(pathB)
+	if err != nil {
+		return false, errors.Wrap(hasher.NewErrRead(err), "failed to compare")
+	}
+
+	defer fileB.Close()
+
+	readerA := bufio.NewReaderSize(fileA, sizeCompareBuf)
+	readerB := bufio.NewReaderSize(fileB, sizeCompareBuf)
+	bufA, bufB := make([]byte, sizeCompareBuf), make([]byte, sizeCompareBuf)
+
+	for {
+		numA, errA := io.ReadFull(readerA, bufA)
+		numB, errB := io.ReadFull(readerB, bufB)
+
+		if !bytes.Equal(bufA[:numA], bufB[:numB]) {
+			return false, nil
+		}
+
+		isEndA := errors.Is(errA, io.EOF) || errors.Is(errA, io.ErrUnexpectedEOF)
+		isEndB := errors.Is(errB, io.EOF) || errors.Is(errB, io.ErrUnexpectedEOF)
+
+		switch {
+		case errA != nil && !isEndA:
+			return false, errors.Wrap(hasher.NewErrRead(errA), "failed to compare")
+		case errB != nil && !isEndB:
+			return false, errors.Wrap(hasher.NewErrRead(errB), "failed to compare")
+		case isEndA || isEndB:
+			return isEndA == isEndB, nil
+		}
+	}
+}
+
+// It returns the number and the noun in the singular or plural, such as "1 group"
+// and "2 groups".
+func plural(num int64, noun string) string {
+	if num == 1 {
+		return "1 " + noun
+	}
+
+	return fmt.Sprintf("%d %ss", num, noun)
+}
+
+// It replaces the duplicate with the hard link to the original. The link is
+// made with a temporary name in the same directory and renamed, so that the
+// duplicate is not lost if it fails.
+func linkDupe(pathOrig, pathDupe string) error {
+	dir, base := filepath.Split(pathDupe)
+
+	pathTemp := filepath.Join(dir, "."+base+".genrawid-link")
+
+	if err := os.Link(pathOrig, pathTemp); err != nil {
+		return errors.Wrap(err, "failed to link")
+	}
+
+	if err := os.Rename(pathTemp, pathDupe); err != nil {
+		os.Remove(pathTemp)
+
+		return errors.Wrap(err, "failed to link")
+	}
+
+	return nil
+}
@@ -0,0 +1,224 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/KEINOS/go-genrawid"
+	"github.com/KEINOS/go-genrawid/pkg/hasher"
+	"github.com/pkg/errors"
+)
+
+// ----------------------------------------------------------------------------
+//  Private Functions
+// ----------------------------------------------------------------------------
+
+// It sets pathFiles if more than one input is given or --recursive option is
+// set. It returns an error if the options that take a single input are used
+// with them.
+func chkOptFiles(args []string) error {
+	if len(args) < 2 && !isRecursive {
+		return nil
+	}
+
+	switch {
+	case inStr != "":
+		return errors.New("--string option can not be used with file arguments")
+	case inVerify != "":
+		return errors.New("--verify option can not be used with more than one input")
+	case len(inSchemes) > 0:
+		return errors.New("--scheme option can not be used with more than one input")
+	}
+
+	pathFiles = args
+
+	return nil
+}
+
+// It expands the glob patterns in args, such as "dir/*.pdf". It is for the
+// shells that do not expand them, such as the command prompt of Windows.
+//
+// The arg is kept as is if it is an existing file or matches nothing. Then it
+// will be reported as not found, the same as the shells do.
+func expandGlobs(args []string) []string {
+	expanded := make([]string, 0, len(args))
+
+	for _, arg := range args {
+		if !strings.ContainsAny(arg, "*?[") {
+			expanded = append(expanded, arg)
+
+			continue
+		}
+
+		if _, err := os.Stat(arg); err == nil {
+			expanded = append(expanded, arg)
+
+			continue
+		}
+
+		matches, err := filepath.Glob(arg)
+		if err != nil || len(matches) == 0 {
+			expanded = append(expanded, arg)
+
+			continue
+		}
+
+		expanded = append(expanded, matches...)
+	}
+
+	return expanded
+}
+
+// It computes the rawids of pathFiles and prints a line of "<rawid>  <path>"
+// for each, like sha256sum. Or the records of --format option. "-" is read from
+// stdin. With --recursive option, the directories are walked. See walkDir.
+//
+// The files are computed concurrently with the workers of --jobs option, while
+// the results are printed in the order of the inputs. See runOrdered.
+//
+// It continues on the inputs that can not be read and prints their errors to
+// stderr. Then it returns filesError at the end.
+func runFiles() error {
+	var (
+		errFiles filesError
+		fmtr     *formatter
+	)
+
+	if inFormat != "" {
+		fmtr = newFormatter(os.Stdout)
+	}
+
+	// err is the error of walkDir if any
+	visit := func(path string, err error) task {
+		return func() func() {
+			var result genrawid.Result
+
+			if err == nil {
+				result, err = computeFile(path)
+			}
+
+			return func() {
+				errFiles.count(path, err)
+
+				switch {
+				case fmtr != nil:
+					fmtr.write(record{path: path, result: result, err: err})
+				case err == nil:
+					//nolint:forbidigo // allow printing to stdout
+					fmt.Printf("%s  %s\n", formatRawid(result.ID), path)
+				}
+			}
+		}
+	}
+
+	_ = runOrdered(func(submit func(task)) error {
+		for _, path := range pathFiles {
+			if isRecursive && path != "-" && isDir(path) {
+				walkDir(path, func(path string, err error) {
+					submit(visit(path, err))
+				})
+
+				continue
+			}
+
+			submit(visit(path, nil))
+		}
+
+		return nil
+	})
+
+	if fmtr != nil {
+		if err := fmtr.end(); err != nil {
+			return err
+		}
+	}
+
+	if errFiles.first != nil {
+		return &errFiles
+	}
+
+	return nil
+}
+
+// It returns the Result of the file. "-" is read from stdin.
+//
+// On --progress option, the file is read as a stream to report the progress
+// instead of being memory-mapped.
+func computeFile(path string) (genrawid.Result, error) {
+	if path == "-" {
+		input, stopProgress := withProgress(path, genrawid.OsStdin)
+		defer stopProgress()
+
+		result, err := genrawid.Compute(input)
+
+		return result, errors.Wrap(err, "failed to read from STDIN")
+	}
+
+	if !isProgress {
+		result, err := genrawid.ComputeFile(path)
+
+		return result, errors.Wrap(err, "failed to read from file")
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return genrawid.Result{}, errors.Wrap(hasher.NewErrRead(err), "failed to read from file")
+	}
+
+	defer file.Close()
+
+	input, stopProgress := withProgress(path, file)
+	defer stopProgress()
+
+	result, err := genrawid.Compute(input)
+
+	return result, errors.Wrap(err, "failed to read from file")
+}
+
+// ----------------------------------------------------------------------------
+//  Type: filesError
+// ----------------------------------------------------------------------------
+
+// filesError is the error of runFiles. The errors of each input are already
+// printed, so the message is only the summary. It unwraps to the first error to
+// decide the exit status.
+//
+// It counts the inputs while running as well.
+type filesError struct {
+	first     error
+	numFailed int
+	numTotal  int
+}
+
+// Error implements the error interface.
+func (e *filesError) Error() string {
+	return fmt.Sprintf("failed to compute the rawids of %d of %d inputs", e.numFailed, e.numTotal)
+}
+
+// It counts the input and prints the error to stderr if any.
+func (e *filesError) count(path string, err error) {
+	e.numTotal++
+
+	if err != nil {
+		e.fail(path, err)
+	}
+}
+
+// It prints the error of the input that is already counted, such as the one
+// failed to compare after computed, and counts it as failed.
+func (e *filesError) fail(path string, err error) {
+	fmt.Fprintf(os.Stderr, "%s: %v\n", path, err)
+
+	if e.first == nil {
+		e.first = err
+	}
+
+	e.numFailed++
+}
+
+// Unwrap returns the error of the first input that failed.
+func (e *filesError) Unwrap() error {
+	return e.first
+}
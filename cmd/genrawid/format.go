@@ -0,0 +1,253 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/KEINOS/go-genrawid"
+	"github.com/pkg/errors"
+)
+
+// Names of the formats of --format option.
+const (
+	formatJSON   = "json"
+	formatNDJSON = "ndjson"
+	formatCSV    = "csv"
+	formatTSV    = "tsv"
+)
+
+// fieldsAll is the names of all the fields of --fields option in the default
+// order.
+var fieldsAll = []string{"path", "size", "dec", "udec", "hex", "base62", "scheme", "error"}
+
+// ----------------------------------------------------------------------------
+//  Type: record
+// ----------------------------------------------------------------------------
+
+// record is the result of an input in the structured output of --format option.
+type record struct {
+	// path is the file path of the input. It is "-" for stdin and empty for
+	// --string option.
+	path   string
+	result genrawid.Result
+	err    error
+}
+
+// It returns the value of the field. The values other than path and error are
+// nil if the input failed.
+func (r record) value(field string) interface{} {
+	switch field {
+	case "path":
+		return r.path
+	case "error":
+		if r.err == nil {
+			return nil
+		}
+
+		return r.err.Error()
+	}
+
+	if r.err != nil {
+		return nil
+	}
+
+	// The rawids are strings since JSON parsers may round large numbers
+	switch field {
+	case "size":
+		return r.result.Size
+	case "dec":
+		return r.result.ID.Dec()
+	case "udec":
+		return r.result.ID.UDec()
+	case "hex":
+		return r.result.ID.Hex()
+	case "base62":
+		return r.result.ID.Base62()
+	case "scheme":
+		return r.result.Scheme
+	}
+
+	return nil
+}
+
+// ----------------------------------------------------------------------------
+//  Type: formatter
+// ----------------------------------------------------------------------------
+
+// formatter writes the records in the format of --format option with the fields
+// of --fields option.
+type formatter struct {
+	output     io.Writer
+	csv        *csv.Writer
+	numRecords int
+}
+
+// It returns the formatter to write to output. It writes the header of CSV and
+// TSV or the beginning of the JSON array as well.
+func newFormatter(output io.Writer) *formatter {
+	fmtr := &formatter{output: output}
+
+	switch inFormat {
+	case formatCSV, formatTSV:
+		fmtr.csv = csv.NewWriter(output)
+
+		if inFormat == formatTSV {
+			fmtr.csv.Comma = '\t'
+		}
+
+		_ = fmtr.csv.Write(fieldsOut)
+	case formatJSON:
+		fmt.Fprint(output, "[")
+	}
+
+	return fmtr
+}
+
+// It writes the record.
+func (f *formatter) write(rec record) {
+	defer func() { f.numRecords++ }()
+
+	if f.csv != nil {
+		row := make([]string, len(fieldsOut))
+
+		for i, field := range fieldsOut {
+			if value := rec.value(field); value != nil {
+				row[i] = fmt.Sprint(value)
+			}
+		}
+
+		_ = f.csv.Write(row)
+
+		return
+	}
+
+	if inFormat == formatNDJSON {
+		fmt.Fprintf(f.output, "%s\n", f.marshal(rec))
+
+		return
+	}
+
+	// An element of the JSON array
+	if f.numRecords > 0 {
+		fmt.Fprint(f.output, ",")
+	}
+
+	fmt.Fprintf(f.output, "\n%s", f.marshal(rec))
+}
+
+// It writes the end of the JSON array and flushes the output. It returns the
+// error of the output if any.
+func (f *formatter) end() error {
+	if inFormat == formatJSON {
+		fmt.Fprint(f.output, "\n]\n")
+	}
+
+	if f.csv != nil {
+		f.csv.Flush()
+
+		return errors.Wrap(f.csv.Error(), "failed to write the output")
+	}
+
+	return nil
+}
+
+// It returns the record as a JSON object. The keys are in the order of the
+// fields, unlike the maps of encoding/json.
+func (f *formatter) marshal(rec record) []byte {
+	var buf bytes.Buffer
+
+	buf.WriteString("{")
+
+	for i, field := range fieldsOut {
+		if i > 0 {
+			buf.WriteString(",")
+		}
+
+		// The keys and the values of string, int64 and nil never fail to marshal
+		key, _ := json.Marshal(field)
+		value, _ := json.Marshal(rec.value(field))
+
+		buf.Write(key)
+		buf.WriteString(":")
+		buf.Write(value)
+	}
+
+	buf.WriteString("}")
+
+	return buf.Bytes()
+}
+
+// ----------------------------------------------------------------------------
+//  Private Functions
+// ----------------------------------------------------------------------------
+
+// It prints the records to stdout in the format of --format option.
+func printRecords(records ...record) error {
+	fmtr := newFormatter(os.Stdout)
+
+	for _, rec := range records {
+		fmtr.write(rec)
+	}
+
+	return fmtr.end()
+}
+
+// It validates --format and --fields options and sets fieldsOut.
+func chkOptFormat() error {
+	if inFormat == "" {
+		if inFields != "" {
+			return errors.New("--fields option is meaningful only with --format option")
+		}
+
+		return nil
+	}
+
+	switch inFormat {
+	case formatJSON, formatNDJSON, formatCSV, formatTSV:
+	default:
+		return errors.Errorf("invalid --format option: %s. it must be json, ndjson, csv or tsv", inFormat)
+	}
+
+	switch {
+	case isCheck:
+		return errors.New("--format option can not be used with --check option")
+	case inVerify != "":
+		return errors.New("--format option can not be used with --verify option")
+	}
+
+	fieldsOut = fieldsAll
+
+	if inFields == "" {
+		return nil
+	}
+
+	fieldsOut = nil
+
+	for _, field := range strings.Split(inFields, ",") {
+		field = strings.TrimSpace(field)
+
+		if !isField(field) {
+			return errors.Errorf("invalid --fields option. unknown field: %s. it must be of %s",
+				field, strings.Join(fieldsAll, ", "))
+		}
+
+		fieldsOut = append(fieldsOut, field)
+	}
+
+	return nil
+}
+
+func isField(name string) bool {
+	for _, field := range fieldsAll {
+		if field == name {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"os"
+	"runtime"
+)
+
+// maxPendingTasks is the number of the tasks that can be in flight at a time.
+// Which bounds the memory to hold the results finished earlier than the ones
+// before them, such as of the small files behind a large file. The workers keep
+// on the following tasks until the limit is reached.
+const maxPendingTasks = 4096
+
+// task is a unit of work of runOrdered. It computes the result, such as the
+// rawid of a file, and returns the function to output it.
+type task func() (output func())
+
+// ----------------------------------------------------------------------------
+//  Private Functions
+// ----------------------------------------------------------------------------
+
+// It returns the number of the workers of --jobs option. 0 or less is the
+// number of the CPUs.
+//
+// It is 1 on --progress option if stderr is a terminal, since the progress of
+// each input is redrawn on the same line.
+func numJobs() int {
+	if isProgress && IsTerminal(os.Stderr) {
+		return 1
+	}
+
+	if inJobs < 1 {
+		return runtime.NumCPU()
+	}
+
+	return inJobs
+}
+
+// It runs the tasks given by feed via submit concurrently with the workers of
+// --jobs option. The outputs of the tasks are called on the calling goroutine in
+// the order of submission, so that the results are deterministic. It returns
+// the error of feed after all the outputs are called.
+//
+// Each task is picked up by the first idle worker. So a large file does not keep
+// the small files behind it from being computed, but only from being output.
+func runOrdered(feed func(submit func(task)) error) error {
+	type pending struct {
+		output func()
+		done   chan struct{}
+	}
+
+	var (
+		queue   = make(chan *pending, maxPendingTasks) // in the order of submission
+		work    = make(chan func())
+		errFeed error
+	)
+
+	for i := numJobs(); i > 0; i-- {
+		go func() {
+			for run := range work {
+				run()
+			}
+		}()
+	}
+
+	go func() {
+		defer close(queue)
+		defer close(work)
+
+		errFeed = feed(func(fn task) {
+			item := &pending{done: make(chan struct{})}
+
+			// Blocks if maxPendingTasks are in flight
+			queue <- item
+			work <- func() {
+				item.output = fn()
+				close(item.done)
+			}
+		})
+	}()
+
+	for item := range queue {
+		<-item.done
+		item.output()
+	}
+
+	// The queue is closed after errFeed is set
+	return errFeed
+}
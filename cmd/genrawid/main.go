@@ -1,9 +1,11 @@
 package main
 
 import (
+	"bytes"
 	"fmt"
 	"hash/crc32"
 	"os"
+	"strings"
 
 	"github.com/KEINOS/go-genrawid"
 	"github.com/KEINOS/go-genrawid/pkg/hasher"
@@ -13,20 +15,49 @@ import (
 )
 
 var (
-	inStr    string // it holds the input string from the arg.
-	inVerify string // it holds the given rawid to compare.
-	lineFeed string // line-feed to use if set.
-	pathFile string // file path to read if set.
-
-	isBase62 bool // outputs the results in base62 if true.
-	isFast   bool // fast mode if true.
-	isFile   bool // read input from file.
-	isHelp   bool // diplays help if true.
-	isHex    bool // outputs the results in hex if true.
-	isLF     bool // line breaks the output if true.
-	isStdin  bool // receive input from STDIN if true.
-	isString bool // receive input from command arg.
-	isVerify bool // compares between the given rawid and calculated rawid.
+	inConcurrency int      // it holds the number of goroutines to hash.
+	inJobs        int      // it holds the number of files to compute concurrently. 0 is the number of CPUs.
+	inHashLen     int      // it holds the byte length of the hash digest. 0 is the default of the algorithm.
+	inExcludes    []string // it holds the glob patterns to skip on --recursive.
+	inFields      string   // it holds the comma separated fields of --format.
+	inFormat      string   // it holds the format of the structured output.
+	inIgnoreFile  string   // it holds the file path of the patterns to skip on --recursive.
+	inIncludes    []string // it holds the glob patterns of the files to read on --recursive.
+	inSchemes     []string // it holds the names of the schemes to compute.
+	pathFiles     []string // file paths to read if more than one input given.
+	fieldsOut     []string // fields to output in the structured output.
+
+	isFollowSymlinks bool // follows the symbolic links on --recursive if true.
+	isRecursive      bool // walks the directories given if true.
+
+	inChkSum  string // it holds the name of the checksum algorithm to use.
+	inConfig  string // it holds the path of the config file.
+	inContext string // it holds the context of the derive-key mode of BLAKE3.
+	inCRCPoly string // it holds the name or the hex of the polynomial of CRC32.
+	inHash    string // it holds the name of the hash algorithm to use.
+	inStr     string // it holds the input string from the arg.
+	inVerify  string // it holds the given rawid to compare.
+	lineFeed  string // line-feed to use if set.
+	pathFile  string // file path to read if set.
+
+	isBase62     bool // outputs the results in base62 if true.
+	isCheck      bool // verifies the rawids of the files listed in the manifests.
+	isFast       bool // fast mode if true.
+	isFile       bool // read input from file.
+	isHelp       bool // diplays help if true.
+	isHex        bool // outputs the results in hex if true.
+	isLF         bool // line breaks the output if true.
+	isLines      bool // computes the rawid of each line of the input if true.
+	isList       bool // lists the available algorithms if true.
+	isNull       bool // computes the rawid of each NUL-terminated record of the input if true.
+	isProgress   bool // reports the progress of reading the inputs to stderr if true.
+	isQuiet      bool // does not print OK on --check if true.
+	isStatus     bool // prints nothing on --check if true. the exit status shows the result.
+	isStripCR    bool // strips the "\r" at the end of each line on --lines if true.
+	isWithRecord bool // prints the record after the rawid on --lines and --null if true.
+	isStdin      bool // receive input from STDIN if true.
+	isString     bool // receive input from command arg.
+	isVerify     bool // compares between the given rawid and calculated rawid.
 )
 
 // ----------------------------------------------------------------------------
@@ -44,39 +75,143 @@ func main() {
 // OsExit is a copy of os.Exit() to ease testing.
 var OsExit = os.Exit
 
-// ExitOnError exits with status 1 if err is an error.
+// Exit statuses of the app on error. Like cmp and diff, the mismatch of the
+// rawids is distinguished from the operational errors, such as the unreadable
+// inputs and the invalid flags. The kinds of the errors that the packages
+// distinguish have their own statuses above them.
+const (
+	ExitMismatch      = 1 // the rawids did not match on --verify or --check, or collided on audit.
+	ExitFailure       = 2 // operational errors, such as unreadable inputs and invalid flags.
+	ExitUnknownAlgo   = 3 // unknown hash or checksum algorithm.
+	ExitInvalidLength = 4 // invalid length of the hash.
+	ExitInvalidRawid  = 5 // invalid rawid given, such as of --verify, convert or the manifest.
+)
+
+// ExitOnError exits with the status of the error if err is an error. See
+// ExitStatus for the statuses.
 func ExitOnError(err error) {
-	if err != nil {
+	if err == nil {
+		return
+	}
+
+	// The results of --check option are already printed as the warnings
+	var errCheck *checkError
+	if !errors.As(err, &errCheck) {
 		fmt.Fprintln(os.Stderr, err.Error())
-		OsExit(1)
 	}
+
+	OsExit(ExitStatus(err))
+}
+
+// errMismatch is the error that the rawids did not match on --verify or --check.
+var errMismatch = errors.New("the two rawids did not match")
+
+// errCollision is the error that different contents share a rawid on audit.
+var errCollision = errors.New("the rawids collided")
+
+// ExitStatus returns the exit status of the given error. It is 0 if err is nil
+// and ExitFailure if the kind of the error is not distinguished.
+func ExitStatus(err error) int {
+	var (
+		errLen    *hasher.ErrInvalidLength
+		errDecode *rawid.ErrDecode
+	)
+
+	// hasher.ErrRead is of the unreadable inputs, so it is ExitFailure as well
+	switch {
+	case err == nil:
+		return 0
+	case errors.Is(err, errMismatch), errors.Is(err, errCollision):
+		return ExitMismatch
+	case errors.Is(err, hasher.ErrUnknownAlgorithm):
+		return ExitUnknownAlgo
+	case errors.As(err, &errLen):
+		return ExitInvalidLength
+	case errors.As(err, &errDecode), errors.Is(err, rawid.ErrOverRange):
+		return ExitInvalidRawid
+	}
+
+	return ExitFailure
 }
 
 // PreRun : parse flag and checks the option status.
 func PreRun() error {
 	// Set and parse flags
 	setFlags()
-	pflag.Parse()
+
+	if err := pflag.CommandLine.Parse(os.Args[1:]); err != nil {
+		return errors.Wrap(err, "invalid flags")
+	}
+
+	// Defaults from the environment variables and the config file
+	if err := loadConfig(); err != nil {
+		return err
+	}
 
 	pflag.Usage = usage // set custom usage for help msg
 
-	args := pflag.Args() // get non-flag command-line arguments.
+	args := expandGlobs(pflag.Args()) // get non-flag command-line arguments.
+
+	// --quiet and --status options check
+	if (isQuiet || isStatus) && !isCheck {
+		return errors.New("--quiet and --status options are meaningful only with --check option")
+	}
+
+	// --check option check. The args are the manifests
+	if isCheck {
+		if err := chkOptCheck(args); err != nil {
+			return err
+		}
+
+		args = nil
+	}
 
-	// Args length validation
-	if len(args) > 1 {
-		return errors.New("too many arguments. more than one file path given")
+	// --recursive option check
+	if err := chkOptRecursive(); err != nil {
+		return err
 	}
 
-	chkOptFile(args)  // file path check
-	chkOptLineFeed()  // --new-line option check
-	chkOptStdin(args) // - (stdin) option check
-	chkOptString()    // --string option check
-	chkOptVerify()    // --verify option check
-	chkModeFast()     // --fast option check
+	// --format and --fields options check
+	if err := chkOptFormat(); err != nil {
+		return err
+	}
+
+	// --lines and --null options check
+	if err := chkOptRecords(args); err != nil {
+		return err
+	}
+
+	// Multiple inputs check
+	if err := chkOptFiles(args); err != nil {
+		return err
+	}
+
+	// --checksum option check
+	if err := chkOptChkSum(); err != nil {
+		return err
+	}
+
+	// --hash, --hash-len and --crc-poly options check
+	if err := chkOptAlgo(); err != nil {
+		return err
+	}
+
+	chkOptConcurrency() // --concurrency option check
+	chkOptContext()     // --context option check
+	chkOptFile(args)    // file path check
+	chkOptLineFeed()    // --new-line option check
+	chkOptStdin(args)   // - (stdin) option check
+	chkOptString()      // --string option check
+	chkOptVerify()      // --verify option check
+	chkModeFast()       // --fast option check
 
 	switch {
 	case isHelp:
 		return nil
+	case isList:
+		return nil
+	case isCheck:
+		return nil
 	case isString:
 		return nil
 	case isStdin:
@@ -89,69 +224,215 @@ func PreRun() error {
 }
 
 // Run is the actual function of the app.
-//
-//nolint:cyclop // complexity is 13 but it's okay.
 func Run() error {
+	// Subcommands have their own flags
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case cmdConvert:
+			return runConvert(os.Args[2:])
+		case cmdConfig:
+			return runConfig(os.Args[2:])
+		case cmdCompletion:
+			return runCompletion(os.Args[2:])
+		case cmdMan:
+			return runMan()
+		case cmdWatch:
+			return runWatch(os.Args[2:])
+		case cmdDupes:
+			return runDupes(os.Args[2:])
+		case cmdAudit:
+			return runAudit(os.Args[2:])
+		}
+	}
+
 	err := PreRun()
 	if err != nil {
 		return errors.Wrap(err, "error during pre-run")
 	}
 
-	//nolint:varnamelen // allow short variable names for readability
-	var id rawid.ID
-
-	switch {
-	case isHelp:
+	if isHelp {
 		pflag.Usage()
 
 		return nil
+	}
+
+	if isList {
+		runListAlgorithms()
+
+		return nil
+	}
+
+	if isCheck {
+		return runCheck()
+	}
+
+	if isLines || isNull {
+		return runRecords()
+	}
+
+	if len(pathFiles) > 1 || isRecursive {
+		return runFiles()
+	}
+
+	if len(inSchemes) > 0 {
+		return runSchemes()
+	}
+
+	var result genrawid.Result
+
+	switch {
 	case isString:
-		// FromString generates whater the input is
-		id, _ = genrawid.FromString(inStr)
+		// Compute generates whatever the input string is
+		result, err = genrawid.Compute(strings.NewReader(inStr))
+		if err != nil {
+			return err
+		}
 	case isFile:
-		id, err = genrawid.FromFile(pathFile)
+		result, err = computeFile(pathFile)
 		if err != nil {
-			return errors.Wrap(err, "failed to read from file")
+			return err
 		}
 	case isStdin:
-		id, err = genrawid.FromStdin()
+		result, err = computeFile("-")
 		if err != nil {
-			return errors.Wrap(err, "failed to read from STDIN")
+			return err
 		}
 	}
 
-	var output string
+	if inFormat != "" {
+		return printRecords(record{path: nameInput(), result: result})
+	}
+
+	return printRawid(result.ID)
+}
+
+// ----------------------------------------------------------------------------
+//  Private Functions
+// ----------------------------------------------------------------------------
+
+func chkOptChkSum() error {
+	algo, err := hasher.ParseChkSumAlgo(inChkSum)
+	if err != nil {
+		return errors.Wrap(err, "invalid --checksum option")
+	}
+
+	hasher.ChkSumAlgo = algo
+
+	return nil
+}
+
+func chkOptConcurrency() {
+	hasher.Concurrency = inConcurrency
+
+	// Unlike the hasher package, 0 means all the CPUs as well
+	if inConcurrency < 1 {
+		hasher.Concurrency = -1
+	}
+}
+
+func chkOptContext() {
+	hasher.Context = inContext
+}
 
-	// Format output
+// It returns the rawid in the format of the output options.
+//
+//nolint:varnamelen // allow short variable names for readability
+func formatRawid(id rawid.ID) string {
 	switch {
 	case isHex:
-		output = fmt.Sprintf("0x%v%v", id.Hex(), lineFeed)
+		return fmt.Sprintf("0x%v", id.Hex())
 	case isBase62:
-		output = fmt.Sprintf("%v%v", id.Base62(), lineFeed)
+		return id.Base62()
 	default:
-		output = fmt.Sprintf("%v%v", id.Dec(), lineFeed)
+		return id.Dec()
 	}
+}
 
+// It prints the rawid to stdout. If --verify option is set, it returns an error
+// if the rawid does not match.
+//
+//nolint:varnamelen // allow short variable names for readability
+func printRawid(id rawid.ID) error {
 	if isVerify {
-		if output != (inVerify + lineFeed) {
-			return errors.Errorf(
-				"the two rawids did not match. Given: %v, Calculated: %v",
-				inVerify,
-				output,
-			)
+		if err := verifyRawid(id); err != nil {
+			return err
 		}
 	}
 
 	// Print the calculated rawid
 	//nolint:forbidigo // allow printing to stdout
-	fmt.Print(output)
+	fmt.Print(formatRawid(id) + lineFeed)
 
 	return nil
 }
 
-// ----------------------------------------------------------------------------
-//  Private Functions
-// ----------------------------------------------------------------------------
+// It returns nil if the rawid of --verify option is the actual one in any of
+// the representations regardless of the output options. See parseRawidAny.
+func verifyRawid(actual rawid.ID) error {
+	expect, err := parseRawidAny(inVerify)
+	if err != nil {
+		return errors.Wrap(err, "invalid --verify option")
+	}
+
+	if !expect.matches(actual) {
+		return errors.Wrapf(errMismatch, "failed to verify. given: %s, calculated: %s", inVerify, formatRawid(actual))
+	}
+
+	return nil
+}
+
+// rawidAny is a rawid given in any of the representations, such as of --verify
+// option and the manifests.
+//
+// The value is tried with each parser since it may be valid in more than one.
+// Such as "1234abcd" in hex and Base62, or the Base62 of digits only. So it
+// holds all the rawids parsed.
+type rawidAny []rawid.ID
+
+// It parses the given rawid in any of the representations regardless of the
+// output options. Such as the signed or unsigned decimal, the hex with or
+// without "0x" in any case, or Base62. It returns the error of the first parser
+// if none of them can parse it.
+func parseRawidAny(given string) (rawidAny, error) {
+	var (
+		parsed   rawidAny
+		errFirst error
+	)
+
+	for _, parse := range []func(string) (rawid.ID, error){
+		rawid.Parse,
+		rawid.NewHex,
+		rawid.NewBase62,
+	} {
+		id, err := parse(given)
+		if err != nil {
+			if errFirst == nil {
+				errFirst = err
+			}
+
+			continue
+		}
+
+		parsed = append(parsed, id)
+	}
+
+	if len(parsed) == 0 {
+		return nil, errFirst
+	}
+
+	return parsed, nil
+}
+
+// It returns true if any of the rawids parsed is the actual one.
+func (r rawidAny) matches(actual rawid.ID) bool {
+	for _, id := range r {
+		if bytes.Equal(id, actual) {
+			return true
+		}
+	}
+
+	return false
+}
 
 func chkModeFast() {
 	genrawid.IsModeFast = isFast
@@ -198,19 +479,46 @@ func chkOptVerify() {
 // Set flag/option values to default.
 func resetFlagValues() {
 	isBase62 = false
+	isCheck = false
 	isFast = false
 	isFile = false
+	isFollowSymlinks = false
 	isHelp = false
 	isHex = false
 	isLF = false
+	isLines = false
+	isList = false
+	isQuiet = false
+	isRecursive = false
+	isNull = false
+	isProgress = false
+	isStatus = false
+	isStripCR = false
+	isWithRecord = false
 	isStdin = false
 	isString = false
 	isVerify = false
 
+	inChkSum = hasher.ChkSumCRC32.String()
+	inConfig = ""
+	inConcurrency = 1
+	inCRCPoly = polyCastagnoli
+	inHash = hasher.HashAlgoBLAKE3.String()
+	inHashLen = 0
+	inJobs = 0
+	inExcludes = nil
+	inFields = ""
+	inFormat = ""
+	fieldsOut = nil
+	inIgnoreFile = ""
+	inIncludes = nil
+	inContext = ""
 	inStr = ""
+	inSchemes = nil
 	inVerify = ""
 	lineFeed = ""
 	pathFile = ""
+	pathFiles = nil
 }
 
 // Set flags to default values.
@@ -219,6 +527,9 @@ func setFlags() {
 	hasher.HashAlgo = hasher.HashAlgoBLAKE3
 	hasher.ChkSumAlgo = hasher.ChkSumCRC32
 	hasher.CRC32Poly = crc32.Castagnoli
+	hasher.HashLen = 0 // the default of the algorithm
+	hasher.Concurrency = 1
+	hasher.Context = ""
 
 	// Set default mode
 	genrawid.IsModeFast = false
@@ -226,19 +537,43 @@ func setFlags() {
 	// Set default flag values
 	resetFlagValues()
 
-	// Initialize flags before parsing
-	if !pflag.Parsed() {
-		pflag.BoolVar(&isBase62, "base62", false, "outputs the rawid in Base62 encoded string (uses: 0-9,a-z,A-Z)")
-		pflag.BoolVarP(&isHelp, "help", "h", false, "displays this help")
-		pflag.BoolVarP(&isFast, "fast", "f", false, "fast mode (uses: XOR16 for checksum)")
-		pflag.BoolVar(&isHex, "hex", false, "outputs the rawid in hex string")
-		pflag.BoolVarP(&isLF, "new-line", "n", false, "line-feed/line-breaks after the output")
-		pflag.StringVarP(&inStr, "string", "s", "", "provide the input via args")
-		pflag.StringVar(&inVerify, "verify", "", "the rawid to verify")
-	}
-
-	// Hide 'fast' flag.
-	// It wasn't fast enough as expected. So, currently it's disabled.
-	_ = pflag.CommandLine.MarkHidden("fast")
-	_ = pflag.CommandLine.MarkHidden("f")
+	// Initialize flags before parsing. The flag set is renewed on each call so
+	// that the flags given in the former calls are not taken as given, which
+	// matters to the precedence of the config. See loadConfig.
+	//
+	// The errors of the flags are returned to exit with ExitFailure via ExitOnError.
+	pflag.CommandLine = pflag.NewFlagSet(os.Args[0], pflag.ContinueOnError)
+
+	pflag.BoolVar(&isBase62, "base62", false, "outputs the rawid in Base62 encoded string (uses: 0-9,a-z,A-Z)")
+	pflag.BoolVarP(&isCheck, "check", "c", false, "reads the lines of \"<rawid>  <path>\" from the files and verifies them")
+	pflag.StringVar(&inChkSum, "checksum", inChkSum, "checksum algorithm to use (crc32, xxhash, xor16, xor8)")
+	pflag.IntVar(&inConcurrency, "concurrency", inConcurrency, "number of threads to hash large inputs with BLAKE3 (0 uses all the CPUs)")
+	pflag.StringVar(&inConfig, "config", "", "config file to read the default settings from. see \"genrawid config show\"")
+	pflag.StringVar(&inCRCPoly, "crc-poly", inCRCPoly, "polynomial of the crc32 checksum (castagnoli, ieee, koopman or in hex such as 0x82f63b78)")
+	pflag.StringVar(&inContext, "context", "", "context string of the BLAKE3 derive-key mode. the rawids differ for each context")
+	pflag.StringVar(&inFields, "fields", "", "comma separated fields to output on --format (path, size, dec, udec, hex, base62, scheme, error)")
+	pflag.StringVar(&inFormat, "format", "", "outputs the results as records in the format (json, ndjson, csv, tsv)")
+	pflag.BoolVarP(&isFollowSymlinks, "follow-symlinks", "L", false, "follows the symbolic links on --recursive")
+	pflag.BoolVarP(&isHelp, "help", "h", false, "displays this help")
+	pflag.StringArrayVar(&inExcludes, "exclude", nil, "glob pattern of the files and directories to skip on --recursive. repeat to give more than one (e.g. \"*.tmp\", \"docs/*.md\", \".git/\")")
+	pflag.BoolVarP(&isFast, "fast", "f", false, "fast mode (uses: XXH64 only. the rawids differ from the regular ones)")
+	pflag.StringVar(&inHash, "hash", inHash, "hash algorithm to use (blake3, sha3-512, xxh64)")
+	pflag.IntVar(&inHashLen, "hash-len", inHashLen, "byte length of the hash digest to compute the checksum of (0 uses the default of the algorithm: 64, or 8 for xxh64)")
+	pflag.BoolVar(&isHex, "hex", false, "outputs the rawid in hex string")
+	pflag.StringVar(&inIgnoreFile, "ignore-file", "", "file of the glob patterns to skip on --recursive. a pattern per line, the same as --exclude")
+	pflag.StringArrayVar(&inIncludes, "include", nil, "glob pattern of the files to read on --recursive. repeat to give more than one")
+	pflag.IntVarP(&inJobs, "jobs", "j", inJobs, "number of files to compute concurrently. the results are in the order of the inputs (0 uses the number of CPUs)")
+	pflag.BoolVar(&isLines, "lines", false, "prints the rawid of each line of the input. the input is STDIN if none given")
+	pflag.BoolVar(&isList, "list-algorithms", false, "lists the algorithms available for --hash, --checksum and --crc-poly")
+	pflag.BoolVarP(&isLF, "new-line", "n", false, "line-feed/line-breaks after the output")
+	pflag.BoolVar(&isProgress, "progress", false, "reports the bytes read, percentage, throughput and ETA to STDERR. a JSON line per second if STDERR is not a terminal")
+	pflag.BoolVar(&isQuiet, "quiet", false, "does not print OK for each verified file on --check")
+	pflag.BoolVarP(&isRecursive, "recursive", "r", false, "reads the files in the directories given recursively. the files are in the lexical order")
+	pflag.StringArrayVar(&inSchemes, "scheme", nil, "scheme to compute the rawid in. repeat to compute more than one in a single pass (e.g. default, fast, v1/sha3-512/512/crc32c)")
+	pflag.BoolVarP(&isNull, "null", "z", false, "prints the rawid of each NUL-terminated record of the input. the outputs are NUL-terminated as well")
+	pflag.BoolVar(&isStatus, "status", false, "prints nothing on --check. the exit status shows the result")
+	pflag.BoolVar(&isStripCR, "strip-cr", false, "strips the \"\\r\" at the end of each line on --lines, such as of the files from Windows. The rawids differ only with --fast since the other algorithms ignore the line breaks")
+	pflag.StringVarP(&inStr, "string", "s", "", "provide the input via args")
+	pflag.BoolVar(&isWithRecord, "with-record", false, "prints \"<rawid>  <record>\" on --lines and --null")
+	pflag.StringVar(&inVerify, "verify", "", "the rawid to verify. any of decimal, hex or Base62")
 }
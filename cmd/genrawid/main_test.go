@@ -1,48 +1,1445 @@
 package main
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
 	"log"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/KEINOS/go-genrawid"
+	"github.com/KEINOS/go-genrawid/pkg/hasher"
+	"github.com/KEINOS/go-genrawid/pkg/rawid"
+	"github.com/KEINOS/go-utiles/util"
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/zenizh/go-capturer"
 )
 
+// TestMain isolates the tests from the config file and the environment variables
+// of the user.
+func TestMain(m *testing.M) {
+	dirHome, err := os.MkdirTemp("", "genrawid-test")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// The user config dir of os.UserConfigDir on each OS
+	for _, name := range []string{"XDG_CONFIG_HOME", "HOME", "AppData"} {
+		os.Setenv(name, dirHome)
+	}
+
+	for _, env := range os.Environ() {
+		if strings.HasPrefix(env, envPrefix) {
+			os.Unsetenv(strings.SplitN(env, "=", 2)[0])
+		}
+	}
+
+	status := m.Run()
+
+	os.RemoveAll(dirHome)
+	os.Exit(status)
+}
+
 // ============================================================================
 //  Tests
 // ============================================================================
 
-// ----------------------------------------------------------------------------
-//  Golden Cases
-// ----------------------------------------------------------------------------
+// ----------------------------------------------------------------------------
+//  Golden Cases
+// ----------------------------------------------------------------------------
+
+//nolint:paralleltest // do not parallelize due to dependency on other tests
+func Test_main_golden_algorithms(t *testing.T) {
+	// The rawids should be the same as the ones of the equivalent schemes
+	for _, test := range []struct {
+		expect string
+		args   []string
+	}{
+		{args: []string{"--hash", "sha3-512"}, expect: "-3894946350167318681"},
+		{args: []string{"--crc-poly", "ieee"}, expect: "-2474118026033754772"},
+		{args: []string{"--crc-poly", "0x82F63B78"}, expect: "-2474118025671277174"},
+		{args: []string{"--hash", "xxh64"}, expect: "4238821250063139159"},
+		{args: []string{"--hash-len", "32"}, expect: "-2474118027065649762"},
+	} {
+		deferRecover := setDummyArgs(t, append(test.args, "../../testdata/msg.txt"))
+
+		out := capturer.CaptureStdout(func() {
+			main()
+		})
+
+		deferRecover()
+
+		assert.Equal(t, test.expect, out, "args: %v", test.args)
+	}
+}
+
+//nolint:paralleltest // do not parallelize due to dependency on other tests
+func Test_main_golden_list_algorithms(t *testing.T) {
+	deferRecover := setDummyArgs(t, []string{"--list-algorithms"})
+	defer deferRecover()
+
+	out := capturer.CaptureStdout(func() {
+		main()
+	})
+
+	assert.Contains(t, out, "  blake3 (default)\n  sha3-512\n  xxh64\n")
+	assert.Contains(t, out, "  crc32 (default)\n  xxhash\n  xor16\n  xor8\n")
+	assert.Contains(t, out, "  castagnoli (default)\n  ieee\n  koopman\n")
+}
+
+//nolint:paralleltest // do not parallelize due to dependency on other tests
+func Test_main_golden_base62(t *testing.T) {
+	// Set args
+	deferRecover := setDummyArgs(t, []string{
+		"--base62",
+		"../../testdata/msg.txt",
+	})
+	defer deferRecover()
+
+	out := capturer.CaptureStdout(func() {
+		main()
+	})
+
+	expect := "j1UNoJA6ku6"
+	actual := out
+	assert.Equal(t, expect, actual)
+}
+
+//nolint:paralleltest // do not parallelize due to dependency on other tests
+func Test_main_golden_checksum(t *testing.T) {
+	for _, test := range []struct {
+		chkSum string
+		expect string
+	}{
+		{"crc32", "0xddaa2ac39b79058a"},
+		{"xxhash", "0xddaa2ac32744aeae"},
+		{"xor8", "0xddaa2ac30a98651b"},
+		{"xor16", "0xddaa2ac30a98963b"},
+	} {
+		// Set args
+		deferRecover := setDummyArgs(t, []string{
+			"--hex",
+			"--checksum",
+			test.chkSum,
+			"../../testdata/msg.txt",
+		})
+
+		out := capturer.CaptureStdout(func() {
+			main()
+		})
+
+		deferRecover()
+
+		expect := test.expect
+		actual := out
+		assert.Equal(t, expect, actual, "checksum algorithm: %s", test.chkSum)
+	}
+}
+
+//nolint:paralleltest // do not parallelize due to dependency on other tests
+func Test_main_golden_concurrency(t *testing.T) {
+	for _, concurrency := range []string{"0", "1", "4"} {
+		// Set args
+		deferRecover := setDummyArgs(t, []string{
+			"--concurrency",
+			concurrency,
+			"../../testdata/dummy.bin",
+		})
+
+		out := capturer.CaptureStdout(func() {
+			main()
+		})
+
+		deferRecover()
+
+		expect := "-2929669798473946006"
+		actual := out
+		assert.Equal(t, expect, actual, "concurrency: %s", concurrency)
+	}
+}
+
+//nolint:paralleltest // do not parallelize due to dependency on other tests
+func Test_main_golden_config(t *testing.T) {
+	pathConfig := filepath.Join(t.TempDir(), "config")
+	require.NoError(t, os.WriteFile(pathConfig, []byte(util.HereDoc(`
+		# Team defaults
+		hash = sha3-512
+		crc-poly = koopman # CRC-32K
+		base62 = true
+		new-line = "true"
+	`)), 0o600))
+
+	pathConfigFast := filepath.Join(t.TempDir(), "config")
+	require.NoError(t, os.WriteFile(pathConfigFast, []byte("fast = true\n"), 0o600))
+
+	for _, test := range []struct {
+		env    map[string]string
+		expect string
+		args   []string
+	}{
+		{
+			// Config file
+			args:   []string{"--config", pathConfig},
+			expect: "hkXo3jujc9Q\n",
+		},
+		{
+			// Environment variables over config file. So is crc-poly of the file
+			// of the same group.
+			args:   []string{},
+			env:    map[string]string{"GENRAWID_CONFIG": pathConfig, "GENRAWID_HASH": "blake3"},
+			expect: "j1UNoJA6ku6\n",
+		},
+		{
+			// Environment variables over the config file of the same group
+			args:   []string{"--config", pathConfigFast},
+			env:    map[string]string{"GENRAWID_HASH": "sha3-512"},
+			expect: "-3894946350167318681",
+		},
+		{
+			// Flags over environment variables
+			args:   []string{"--config", pathConfig, "--hash", "sha3-512", "--crc-poly", "castagnoli"},
+			env:    map[string]string{"GENRAWID_HASH": "blake3", "GENRAWID_BASE62": "false"},
+			expect: "-3894946350167318681\n",
+		},
+		{
+			// Flags over the environment variables of the same group
+			args:   []string{"--base62"},
+			env:    map[string]string{"GENRAWID_HEX": "true"},
+			expect: "j1UNoJA6ku6",
+		},
+		{
+			args:   []string{"--hash", "sha3-512"},
+			env:    map[string]string{"GENRAWID_FAST": "true"},
+			expect: "-3894946350167318681",
+		},
+		{
+			args:   []string{"--fast"},
+			env:    map[string]string{"GENRAWID_CONFIG": pathConfig, "GENRAWID_CHECKSUM": "xor8"},
+			expect: "537Qt748IHZ\n", // base62 of the config file
+		},
+		{
+			args:   []string{"--crc-poly", "ieee", "--hex"},
+			env:    map[string]string{"GENRAWID_CHECKSUM": "xor8"},
+			expect: "0xddaa2ac385de0d6c",
+		},
+	} {
+		deferRecover := setDummyArgs(t, append(test.args, "-s", "abcdefgh"))
+		recoverEnv := setEnvs(t, test.env)
+
+		out := capturer.CaptureStdout(func() {
+			main()
+		})
+
+		recoverEnv()
+		deferRecover()
+
+		assert.Equal(t, test.expect, out, "args: %v, env: %v", test.args, test.env)
+	}
+}
+
+//nolint:paralleltest // do not parallelize due to dependency on other tests
+func Test_main_golden_config_show(t *testing.T) {
+	pathConfig := filepath.Join(t.TempDir(), "config")
+	require.NoError(t, os.WriteFile(pathConfig, []byte("hash = sha3-512\ncontext = \"acme #1\"\n"), 0o600))
+
+	deferRecover := setDummyArgs(t, []string{"config", "show", "--hex"})
+	defer deferRecover()
+
+	recoverEnv := setEnvs(t, map[string]string{"GENRAWID_CONFIG": pathConfig, "GENRAWID_CHECKSUM": "xor16"})
+	defer recoverEnv()
+
+	out := capturer.CaptureStdout(func() {
+		main()
+	})
+
+	for _, expect := range []string{
+		"# Config file: " + pathConfig + "\n",
+		"checksum = xor16 # env GENRAWID_CHECKSUM\n",
+		"context = \"acme #1\" # file " + pathConfig + "\n",
+		"hash = blake3 # default (file " + pathConfig + " is ignored for the environment variables)\n",
+		"hex = true # flag\n",
+		"base62 = false # default\n",
+	} {
+		assert.Contains(t, out, expect)
+	}
+
+	// The environment variables of the same group as the flags are ignored
+	recoverArgs := setDummyArgs(t, []string{"config", "show", "--fast"})
+	defer recoverArgs()
+
+	out = capturer.CaptureStdout(func() {
+		main()
+	})
+
+	for _, expect := range []string{
+		"checksum = crc32 # default (env GENRAWID_CHECKSUM is ignored for the flags)\n",
+		"hash = blake3 # default (file " + pathConfig + " is ignored for the flags)\n",
+		"fast = true # flag\n",
+	} {
+		assert.Contains(t, out, expect)
+	}
+}
+
+//nolint:paralleltest // do not parallelize due to dependency on other tests
+func Test_main_golden_completion(t *testing.T) {
+	for _, test := range []struct {
+		shell   string
+		expects []string
+	}{
+		{
+			shell: "bash",
+			expects: []string{
+				"--hash) COMPREPLY=($(compgen -W \"blake3 sha3-512 xxh64\" -- \"$cur\")); return ;;",
+				"--format) COMPREPLY=($(compgen -W \"json ndjson csv tsv\" -- \"$cur\")); return ;;",
+				"--config) COMPREPLY=($(compgen -f -- \"$cur\")); return ;;",
+				"compgen -W \"convert config watch dupes audit completion man\"",
+				"--manifest) COMPREPLY=($(compgen -f -- \"$cur\")); return ;;",
+				// The main flags that watch rejects are left out, such as --check
+				"compgen -W \"--base62 --checksum --concurrency --config --context --crc-poly --exclude --fast -f" +
+					" --follow-symlinks -L --hash --hash-len --help -h --hex --ignore-file --include --interval" +
+					" --jobs -j --manifest --new-line -n --progress --recursive -r\"",
+				"complete -o filenames -F _genrawid genrawid",
+			},
+		},
+		{
+			shell: "zsh",
+			expects: []string{
+				"#compdef genrawid",
+				"'--hash=[hash algorithm to use (blake3, sha3-512, xxh64)]:hash:(blake3 sha3-512 xxh64)'",
+				"'--crc-poly=[",
+				"'(-c --check)'{-c,--check}'[",
+				":ignore-file:_files'",
+				"'*--exclude=[",
+				"'*:file:_files'",
+				"compdef _genrawid genrawid",
+			},
+		},
+		{
+			shell: "fish",
+			expects: []string{
+				"complete -c genrawid -n '__fish_use_subcommand' -a convert",
+				"-l hash -x -a 'blake3 sha3-512 xxh64'",
+				"-l checksum -x -a 'crc32 xxhash xor16 xor8'",
+				"-l to -x -a 'dec udec hex base62 int64'",
+				"-l config -r -F",
+				"-l check -s c -d ",
+			},
+		},
+	} {
+		recoverArgs := setDummyArgs(t, []string{"completion", test.shell})
+
+		out := capturer.CaptureStdout(func() {
+			main()
+		})
+
+		recoverArgs()
+
+		for _, expect := range test.expects {
+			assert.Contains(t, out, expect, "shell: %s", test.shell)
+		}
+	}
+}
+
+//nolint:paralleltest // do not parallelize due to dependency on other tests
+func Test_main_golden_man(t *testing.T) {
+	deferRecover := setDummyArgs(t, []string{"man"})
+	defer deferRecover()
+
+	out := capturer.CaptureStdout(func() {
+		main()
+	})
+
+	for _, expect := range []string{
+		".TH GENRAWID 1 ",
+		".SH OPTIONS\n",
+		".TP\n\\fB\\-\\-hash\\fR \\fIstring\\fR\nhash algorithm to use (blake3, sha3\\-512, xxh64) (default \"blake3\")\n",
+		".TP\n\\fB\\-c\\fR, \\fB\\-\\-check\\fR\n",
+		"\\fB\\-\\-to\\fR \\fIstring\\fR\n",
+		".B GENRAWID_CRC_POLY\n",
+		".SH \"EXIT STATUS\"\n",
+	} {
+		assert.Contains(t, out, expect)
+	}
+}
+
+//nolint:paralleltest // do not parallelize due to dependency on other tests
+func Test_main_golden_progress(t *testing.T) {
+	for _, test := range []struct {
+		expect string
+		args   []string
+		isTTY  bool
+	}{
+		{
+			args:   []string{"--progress", "../../testdata/msg.txt"},
+			expect: `{"path":"../../testdata/msg.txt","bytes":8,"total":8,"percent":100,"bytes_per_sec":`,
+		},
+		{
+			args:   []string{"--progress", "--scheme", "default", "--scheme", "fast", "-"},
+			expect: `{"path":"-","bytes":8,"total":8,"percent":100,"bytes_per_sec":`,
+		},
+		{
+			args:   []string{"--progress", "../../testdata/msg.txt"},
+			expect: "\r\x1b[K../../testdata/msg.txt: 8 B / 8 B (100.0%)  ",
+			isTTY:  true,
+		},
+	} {
+		recoverArgs := setDummyArgs(t, test.args)
+		recoverStdin := mockSTDIN(t, "abcdefgh")
+
+		oldIsTerminal := IsTerminal
+		IsTerminal = func(*os.File) bool { return test.isTTY }
+
+		var stdout string
+
+		stderr := capturer.CaptureStderr(func() {
+			stdout = capturer.CaptureStdout(func() {
+				main()
+			})
+		})
+
+		IsTerminal = oldIsTerminal
+
+		recoverStdin()
+		recoverArgs()
+
+		// The progress does not affect stdout
+		assert.Contains(t, stdout, "-2474118025671277174", "args: %v", test.args)
+		assert.NotContains(t, stdout, "bytes", "args: %v", test.args)
+
+		assert.Contains(t, stderr, test.expect, "args: %v", test.args)
+		assert.True(t, strings.HasSuffix(stderr, "\n"), "the final report should end with a line break")
+
+		if !test.isTTY {
+			assert.Contains(t, stderr, `"done":true}`, "args: %v", test.args)
+		}
+	}
+}
+
+func Test_formatProgress(t *testing.T) {
+	t.Parallel()
+
+	total, percent, eta := int64(40*1024*1024*1024), 3.0, 112.4
+
+	for _, test := range []struct {
+		expect string
+		stat   progressStat
+	}{
+		{
+			stat: progressStat{
+				Path: "disk.img", Bytes: 1288490189, Total: &total, Percent: &percent,
+				BytesPerSec: 350.2 * 1024 * 1024, ETASec: &eta,
+			},
+			expect: "disk.img: 1.2 GiB / 40.0 GiB (3.0%)  350.2 MiB/s  ETA 1m52s",
+		},
+		{
+			stat:   progressStat{Path: "-", Bytes: 1536, BytesPerSec: 512},
+			expect: "-: 1.5 KiB  512 B/s",
+		},
+		{
+			stat:   progressStat{Path: "-", Bytes: 8, Total: &total, Percent: &percent, ETASec: &eta, Done: true},
+			expect: "-: 8 B / 40.0 GiB (3.0%)  0 B/s",
+		},
+	} {
+		assert.Equal(t, test.expect, formatProgress(test.stat))
+	}
+}
+
+//nolint:paralleltest // do not parallelize due to dependency on other tests
+func Test_main_golden_context(t *testing.T) {
+	// Set args
+	deferRecover := setDummyArgs(t, []string{
+		"--context",
+		"acme 2026 manifest v1",
+		"../../testdata/msg.txt",
+	})
+	defer deferRecover()
+
+	out := capturer.CaptureStdout(func() {
+		main()
+	})
+
+	expect := "3728148194611179052"
+	actual := out
+	assert.Equal(t, expect, actual)
+}
+
+//nolint:paralleltest // do not parallelize due to dependency on other tests
+func Test_main_golden_convert(t *testing.T) {
+	for _, test := range []struct {
+		expect string
+		args   []string
+	}{
+		{args: []string{"--to", "base62", "-2474118025671277174"}, expect: "j1UNoJA6ku6\n"},
+		{args: []string{"--to", "dec", "j1UNoJA6ku6"}, expect: "-2474118025671277174\n"},
+		{args: []string{"--to", "udec", "0xddaa2ac39b79058a"}, expect: "15972626048038274442\n"},
+		{args: []string{"--to", "hex", "15972626048038274442", "-1"}, expect: "0xddaa2ac39b79058a\n0xffffffffffffffff\n"},
+		{args: []string{"--to", "int64", "lYGhA16ahyf"}, expect: "-1\n"},
+		{args: []string{"--from", "base62", "--to", "hex", "12345"}, expect: "0x0000000000e8ec09\n"},
+	} {
+		deferRecover := setDummyArgs(t, append([]string{"convert"}, test.args...))
+
+		out := capturer.CaptureStdout(func() {
+			main()
+		})
+
+		deferRecover()
+
+		assert.Equal(t, test.expect, out, "args: %v", test.args)
+	}
+}
+
+//nolint:paralleltest // do not parallelize due to dependency on other tests
+func Test_main_golden_convert_stdin(t *testing.T) {
+	deferRecover := setDummyArgs(t, []string{"convert", "--to", "base62"})
+	defer deferRecover()
+
+	recoverStdin := mockSTDIN(t, "-2474118025671277174\n\n-1\n")
+	defer recoverStdin()
+
+	out := capturer.CaptureStdout(func() {
+		main()
+	})
+
+	// Empty lines are kept to align the outputs with the inputs
+	assert.Equal(t, "j1UNoJA6ku6\n\nlYGhA16ahyf\n", out)
+}
+
+//nolint:paralleltest // do not parallelize due to dependency on other tests
+func Test_main_golden_file(t *testing.T) {
+	// Set args
+	deferRecover := setDummyArgs(t, []string{
+		"../../testdata/msg.txt",
+	})
+	defer deferRecover()
+
+	out := capturer.CaptureStdout(func() {
+		main()
+	})
+
+	expect := "-2474118025671277174"
+	actual := out
+	assert.Equal(t, expect, actual)
+}
+
+//nolint:paralleltest // do not parallelize due to dependency on other tests
+func Test_main_golden_files(t *testing.T) {
+	// Set args. The glob is expanded if the shell did not
+	deferRecover := setDummyArgs(t, []string{
+		"--hex",
+		"../../testdata/msg.txt",
+		"../../testdata/ms?.txt",
+	})
+	defer deferRecover()
+
+	out := capturer.CaptureStdout(func() {
+		main()
+	})
+
+	expect := util.HereDoc(`
+		0xddaa2ac39b79058a  ../../testdata/msg.txt
+		0xddaa2ac39b79058a  ../../testdata/msg.txt
+	`)
+	actual := out
+	assert.Equal(t, expect, actual)
+}
+
+//nolint:paralleltest // do not parallelize due to dependency on other tests
+func Test_main_golden_check(t *testing.T) {
+	// Any representation of rawid is accepted
+	pathManifest := writeManifest(t, util.HereDoc(`
+		# comment and empty lines are skipped
+
+		-2474118025671277174  ../../testdata/msg.txt
+		0xddaa2ac39b79058a  ../../testdata/msg.txt
+		j1UNoJA6ku6  ../../testdata/msg.txt
+		ddaa2ac39b79058a  ../../testdata/msg.txt
+		DDAA2AC39B79058A  ../../testdata/msg.txt
+		15972626048038274442  ../../testdata/msg.txt
+	`))
+
+	for _, test := range []struct {
+		expect string
+		args   []string
+	}{
+		{
+			args: []string{"--check", pathManifest},
+			expect: util.HereDoc(`
+				../../testdata/msg.txt: OK
+				../../testdata/msg.txt: OK
+				../../testdata/msg.txt: OK
+				../../testdata/msg.txt: OK
+				../../testdata/msg.txt: OK
+				../../testdata/msg.txt: OK
+			`),
+		},
+		{args: []string{"-c", "--quiet", pathManifest}, expect: ""},
+		{args: []string{"-c", "--status", pathManifest}, expect: ""},
+	} {
+		deferRecover := setDummyArgs(t, test.args)
+
+		out := capturer.CaptureOutput(func() {
+			main()
+		})
+
+		deferRecover()
+
+		assert.Equal(t, test.expect, out, "args: %v", test.args)
+	}
+}
+
+//nolint:paralleltest // do not parallelize due to dependency on other tests
+func Test_main_golden_recursive(t *testing.T) {
+	dirRoot := writeTree(t, map[string]string{
+		"b/sub/y.md":  "abcdefgh",
+		"a/x.txt":     "abcdefgh",
+		"b/z.tmp":     "abcdefgh",
+		".git/config": "abcdefgh",
+	})
+
+	pathIgnore := filepath.Join(t.TempDir(), "ignore")
+	require.NoError(t, os.WriteFile(pathIgnore, []byte("# comment\n.git/\n"), 0o600))
+
+	for _, test := range []struct {
+		args   []string
+		expect []string
+	}{
+		{
+			args:   []string{"-r", dirRoot},
+			expect: []string{".git/config", "a/x.txt", "b/sub/y.md", "b/z.tmp"},
+		},
+		{
+			args:   []string{"--recursive", "--exclude", "*.tmp", "--ignore-file", pathIgnore, dirRoot},
+			expect: []string{"a/x.txt", "b/sub/y.md"},
+		},
+		{
+			args:   []string{"-r", "--exclude", "b/sub/", "--include", "*.txt", "--include", "b/*", dirRoot},
+			expect: []string{"a/x.txt", "b/z.tmp"},
+		},
+	} {
+		deferRecover := setDummyArgs(t, test.args)
+
+		out := capturer.CaptureStdout(func() {
+			main()
+		})
+
+		deferRecover()
+
+		expect := ""
+		for _, path := range test.expect {
+			expect += "-2474118025671277174  " + filepath.Join(dirRoot, path) + "\n"
+		}
+
+		assert.Equal(t, expect, out, "args: %v", test.args)
+	}
+}
+
+//nolint:paralleltest // do not parallelize due to dependency on other tests
+func Test_main_golden_jobs(t *testing.T) {
+	contents := map[string]string{}
+	for i := 0; i < 50; i++ {
+		contents[fmt.Sprintf("f%02d.txt", i)] = strings.Repeat("a", i)
+	}
+
+	contents["f00.txt"] = strings.Repeat("abcdefgh", 1024*1024) // larger one first
+
+	dirRoot := writeTree(t, contents)
+
+	var expect string
+
+	for _, test := range []struct {
+		args []string
+	}{
+		{args: []string{"-j", "1", "-r", dirRoot}},
+		{args: []string{"-j", "4", "-r", dirRoot}},
+		{args: []string{"--jobs", "0", "-r", dirRoot}},
+		{args: []string{"-r", dirRoot}},
+	} {
+		deferRecover := setDummyArgs(t, test.args)
+
+		out := capturer.CaptureStdout(func() {
+			main()
+		})
+
+		deferRecover()
+
+		// The outputs are the same as the sequential one in the lexical order
+		if expect == "" {
+			expect = out
+
+			require.True(t, strings.HasSuffix(strings.SplitN(out, "\n", 2)[0], "f00.txt"))
+			require.Equal(t, 50, strings.Count(out, "\n"))
+		}
+
+		assert.Equal(t, expect, out, "args: %v", test.args)
+	}
+
+	// --check
+	pathManifest := writeManifest(t, expect+"invalid line\n"+expect)
+
+	deferRecover := setDummyArgs(t, []string{"-j", "4", "--check", pathManifest})
+	defer deferRecover()
+
+	var status int
+
+	recoverOsExit := captureExitStatus(t, &status)
+	defer recoverOsExit()
+
+	var stderr string
+
+	out := capturer.CaptureStdout(func() {
+		stderr = capturer.CaptureStderr(func() {
+			main()
+		})
+	})
+
+	assert.Equal(t, ExitInvalidRawid, status)
+	assert.Contains(t, stderr, pathManifest+":51: improperly formatted line")
+
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+
+	require.Len(t, lines, 100)
+	assert.Equal(t, filepath.Join(dirRoot, "f00.txt")+": OK", lines[0])
+	assert.Equal(t, filepath.Join(dirRoot, "f49.txt")+": OK", lines[99])
+}
+
+//nolint:paralleltest // do not parallelize due to dependency on other tests
+func Test_runOrdered(t *testing.T) {
+	oldJobs := inJobs
+	defer func() { inJobs = oldJobs }()
+
+	inJobs = 8
+
+	const numTasks = maxPendingTasks + 100 // more than the pending ones
+
+	var outputs []int
+
+	err := runOrdered(func(submit func(task)) error {
+		for i := 0; i < numTasks; i++ {
+			i := i
+
+			submit(func() func() {
+				if i%100 == 0 {
+					time.Sleep(time.Millisecond) // finish later than the following ones
+				}
+
+				return func() { outputs = append(outputs, i) }
+			})
+		}
+
+		return errors.New("error of feed")
+	})
+
+	require.EqualError(t, err, "error of feed")
+	require.Len(t, outputs, numTasks)
+
+	for i, output := range outputs {
+		require.Equal(t, i, output, "the outputs should be in the order of submission")
+	}
+}
+
+//nolint:paralleltest // do not parallelize due to dependency on other tests
+func Test_main_golden_watch(t *testing.T) {
+	dirRoot := writeTree(t, map[string]string{
+		"a/x.txt": "abcdefgh",
+		"b/y.tmp": "foo",
+	})
+
+	pathManifest := filepath.Join(dirRoot, "manifest.txt")
+
+	// Stop after the first scan
+	oldNewWatchContext := NewWatchContext
+	defer func() { NewWatchContext = oldNewWatchContext }()
+
+	NewWatchContext = func() (context.Context, context.CancelFunc) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		return ctx, cancel
+	}
+
+	for _, test := range []struct {
+		expect string
+		args   []string
+	}{
+		{
+			args: []string{"watch", dirRoot},
+			expect: `{"event":"created","path":"` + filepath.Join(dirRoot, "a/x.txt") + `","size":8,"rawid":"-2474118025671277174"}` + "\n" +
+				`{"event":"created","path":"` + filepath.Join(dirRoot, "b/y.tmp") + `","size":3,"rawid":"351486625072160940"}` + "\n",
+		},
+		{
+			args:   []string{"watch", "--base62", "--exclude", "*.tmp", dirRoot},
+			expect: `{"event":"created","path":"` + filepath.Join(dirRoot, "a/x.txt") + `","size":8,"rawid":"j1UNoJA6ku6"}` + "\n",
+		},
+		{
+			args:   []string{"watch", "--manifest", pathManifest, dirRoot},
+			expect: "",
+		},
+	} {
+		deferRecover := setDummyArgs(t, test.args)
+
+		out := capturer.CaptureStdout(func() {
+			main()
+		})
+
+		deferRecover()
+
+		assert.Equal(t, test.expect, out, "args: %v", test.args)
+	}
+
+	// The manifest is the same as of --recursive and does not list itself
+	manifest, err := os.ReadFile(pathManifest)
+	require.NoError(t, err)
+
+	assert.Equal(t,
+		"-2474118025671277174  "+filepath.Join(dirRoot, "a/x.txt")+"\n"+
+			"351486625072160940  "+filepath.Join(dirRoot, "b/y.tmp")+"\n",
+		string(manifest))
+}
+
+//nolint:paralleltest // do not parallelize due to dependency on other tests
+func Test_main_watch_error(t *testing.T) {
+	for _, test := range []struct {
+		expect string
+		args   []string
+		status int
+	}{
+		{
+			args:   []string{"watch"},
+			expect: "missing directory. usage: genrawid watch [flags] <dir>...",
+			status: ExitFailure,
+		},
+		{
+			args:   []string{"watch", "../../testdata/msg.txt"},
+			expect: "not a directory: ../../testdata/msg.txt",
+			status: ExitFailure,
+		},
+		{
+			args:   []string{"watch", "--interval", "0s", "../../testdata"},
+			expect: "invalid --interval option: 0s. it must be positive",
+			status: ExitFailure,
+		},
+		{
+			args:   []string{"watch", "--format", "json", "../../testdata"},
+			expect: "--format option can not be used with watch",
+			status: ExitFailure,
+		},
+		{
+			// Meaningful only with --check option
+			args:   []string{"watch", "--quiet", "../../testdata"},
+			expect: "--quiet option can not be used with watch",
+			status: ExitFailure,
+		},
+		{
+			args:   []string{"watch", "--hash", "md5", "../../testdata"},
+			expect: "unknown hash algorithm: md5",
+			status: ExitUnknownAlgo,
+		},
+	} {
+		recoverArgs := setDummyArgs(t, test.args)
+
+		// Mock os.Exit to capture exit status
+		var status int
+
+		recoverOsExit := captureExitStatus(t, &status)
+
+		// Capture error
+		out := capturer.CaptureStderr(func() {
+			main()
+		})
+
+		recoverOsExit()
+		recoverArgs()
+
+		assert.Equal(t, test.status, status, "args: %v", test.args)
+		assert.Contains(t, out, test.expect, "args: %v", test.args)
+	}
+}
+
+//nolint:paralleltest // do not parallelize due to dependency on other tests
+func Test_watcher_scan(t *testing.T) {
+	dirRoot := writeTree(t, map[string]string{
+		"a.txt":     "abcdefgh",
+		"sub/b.txt": "foo",
+	})
+
+	pathA, pathB, pathC := filepath.Join(dirRoot, "a.txt"), filepath.Join(dirRoot, "sub/b.txt"), filepath.Join(dirRoot, "c.txt")
+
+	setFlags()
+
+	watch := newWatcher([]string{dirRoot}, "")
+
+	assert.Equal(t, []watchEvent{
+		{Event: eventCreated, Path: pathA, Size: 8, Rawid: "-2474118025671277174"},
+		{Event: eventCreated, Path: pathB, Size: 3, Rawid: "351486625072160940"},
+	}, watch.scan())
+
+	assert.Empty(t, watch.scan(), "nothing changed")
+
+	// Touch only. The content is the same
+	later := time.Now().Add(time.Hour)
+	require.NoError(t, os.Chtimes(pathA, later, later))
+
+	assert.Empty(t, watch.scan(), "the files of the same rawid are not reported")
+
+	// Modify, create and delete
+	require.NoError(t, os.WriteFile(pathA, []byte("foo"), 0o600))
+	require.NoError(t, os.WriteFile(pathC, []byte("abcdefgh"), 0o600))
+	require.NoError(t, os.Remove(pathB))
+
+	assert.Equal(t, []watchEvent{
+		{Event: eventModified, Path: pathA, Size: 3, Rawid: "351486625072160940", Previous: "-2474118025671277174"},
+		{Event: eventCreated, Path: pathC, Size: 8, Rawid: "-2474118025671277174"},
+		{Event: eventDeleted, Path: pathB, Size: 3, Rawid: "351486625072160940"},
+	}, watch.scan())
+
+	// The deletions are not detected while the directory can not be read
+	require.NoError(t, os.Remove(pathC))
+
+	watch.roots = append(watch.roots, filepath.Join(dirRoot, "unknown"))
+
+	capturer.CaptureStderr(func() {
+		assert.Empty(t, watch.scan())
+	})
+
+	watch.roots = watch.roots[:1]
+
+	assert.Equal(t, []watchEvent{
+		{Event: eventDeleted, Path: pathC, Size: 8, Rawid: "-2474118025671277174"},
+	}, watch.scan())
+}
+
+//nolint:paralleltest // do not parallelize due to dependency on other tests
+func Test_main_golden_dupes(t *testing.T) {
+	for _, test := range []struct {
+		expect  string
+		summary string
+		args    []string
+	}{
+		{
+			args:    []string{"--summary"},
+			expect:  "-2474118025671277174  x/a.txt\n-2474118025671277174  y/a.txt\n-2474118025671277174  y/b.txt\n\n351486625072160940  x/f.txt\n351486625072160940  y/f.txt\n",
+			summary: "2 groups, 3 duplicate files, 19 B (19 bytes) reclaimable\n",
+		},
+		{
+			args:   []string{"--exclude", "y/"},
+			expect: "",
+		},
+		{
+			// The same size and digest but the line breaks differ
+			args:   []string{"--link", "--include", "*/lb.txt"},
+			expect: "",
+		},
+		{
+			args:   []string{"--fast", "--exclude", "f.txt"},
+			expect: "4238821247360054455  x/a.txt\n4238821247360054455  y/a.txt\n4238821247360054455  y/b.txt\n",
+		},
+		{
+			args:    []string{"--link", "--summary", "--base62", "--include", "*/f.txt"},
+			expect:  "pXOn8MOnJW  x/f.txt\npXOn8MOnJW  y/f.txt\n",
+			summary: "1 group, 1 duplicate file, 3 B (3 bytes) reclaimed\n",
+		},
+	} {
+		dirRoot := writeTree(t, map[string]string{
+			"x/a.txt":  "abcdefgh",
+			"y/a.txt":  "abcdefgh",
+			"y/b.txt":  "abcdefgh",
+			"y/c.txt":  "abcdefgX", // same size, different content
+			"x/f.txt":  "foo",
+			"y/f.txt":  "foo",
+			"x/e.txt":  "", // empty files are skipped
+			"y/e.txt":  "",
+			"x/lb.txt": "ab\n\n", // the digest ignores the line breaks
+			"y/lb.txt": "a\nb\n",
+		})
+
+		deferRecover := setDummyArgs(t, append(append([]string{"dupes"}, test.args...), dirRoot))
+
+		var stdout string
+
+		stderr := capturer.CaptureStderr(func() {
+			stdout = capturer.CaptureStdout(func() {
+				main()
+			})
+		})
+
+		deferRecover()
+
+		assert.Equal(t, test.expect, strings.ReplaceAll(stdout, dirRoot+string(filepath.Separator), ""), "args: %v", test.args)
+		assert.Equal(t, test.summary, stderr, "args: %v", test.args)
+
+		content, err := os.ReadFile(filepath.Join(dirRoot, "y/lb.txt"))
+		require.NoError(t, err)
+		assert.Equal(t, "a\nb\n", string(content), "the different content should not be linked")
+
+		// The hard links are not duplicates any more
+		if test.summary != "" && strings.Contains(test.summary, "reclaimed") {
+			infoX, err := os.Stat(filepath.Join(dirRoot, "x/f.txt"))
+			require.NoError(t, err)
+
+			infoY, err := os.Stat(filepath.Join(dirRoot, "y/f.txt"))
+			require.NoError(t, err)
+
+			assert.True(t, os.SameFile(infoX, infoY), "the duplicate should be replaced with the hard link")
+		}
+	}
+}
+
+//nolint:paralleltest // do not parallelize due to dependency on other tests
+func Test_main_dupes_error(t *testing.T) {
+	for _, test := range []struct {
+		expect string
+		args   []string
+		status int
+	}{
+		{
+			args:   []string{"dupes"},
+			expect: "missing paths. usage: genrawid dupes [flags] <path>...",
+			status: ExitFailure,
+		},
+		{
+			args:   []string{"dupes", "-"},
+			expect: "STDIN can not be used with dupes",
+			status: ExitFailure,
+		},
+		{
+			args:   []string{"dupes", "../../testdata/unknown.txt", "../../testdata/msg.txt"},
+			expect: "../../testdata/unknown.txt: failed to read from file",
+			status: ExitFailure,
+		},
+	} {
+		recoverArgs := setDummyArgs(t, test.args)
+
+		// Mock os.Exit to capture exit status
+		var status int
+
+		recoverOsExit := captureExitStatus(t, &status)
+
+		// Capture error
+		out := capturer.CaptureStderr(func() {
+			main()
+		})
+
+		recoverOsExit()
+		recoverArgs()
+
+		assert.Equal(t, test.status, status, "args: %v", test.args)
+		assert.Contains(t, out, test.expect, "args: %v", test.args)
+	}
+}
+
+func Test_isSameContent(t *testing.T) {
+	t.Parallel()
+
+	large := strings.Repeat("abcdefgh", sizeCompareBuf/4) // over the buffer
+
+	dirRoot := writeTree(t, map[string]string{
+		"a": large + "x",
+		"b": large + "x",
+		"c": large + "y",
+		"d": large,
+	})
+
+	for _, test := range []struct {
+		pathA  string
+		pathB  string
+		expect bool
+	}{
+		{"a", "b", true},
+		{"a", "c", false},
+		{"a", "d", false},
+		{"d", "a", false},
+	} {
+		isSame, err := isSameContent(filepath.Join(dirRoot, test.pathA), filepath.Join(dirRoot, test.pathB))
+
+		require.NoError(t, err)
+		assert.Equal(t, test.expect, isSame, "%s and %s", test.pathA, test.pathB)
+	}
+
+	_, err := isSameContent(filepath.Join(dirRoot, "a"), filepath.Join(dirRoot, "unknown"))
+
+	require.ErrorIs(t, err, hasher.ErrRead)
+}
+
+//nolint:paralleltest // do not parallelize due to dependency on other tests
+func Test_groupBytes_error(t *testing.T) {
+	dirRoot := writeTree(t, map[string]string{
+		"a": "abcdefgh",
+		"b": "abcdefgh",
+	})
+
+	// The files are counted when computed, such as by findDupes
+	var errFiles filesError
+
+	candidates := make([]*dupe, 0, 3)
+
+	for _, name := range []string{"a", "b", "c"} {
+		candidates = append(candidates, &dupe{path: filepath.Join(dirRoot, name)})
+		errFiles.count(filepath.Join(dirRoot, name), nil)
+	}
+
+	// "c" is removed after computed
+	var groups [][]*dupe
+
+	out := capturer.CaptureStderr(func() {
+		groups = groupBytes(candidates, &errFiles)
+	})
+
+	require.Len(t, groups, 1)
+	assert.Len(t, groups[0], 2)
+	assert.Contains(t, out, filepath.Join(dirRoot, "c")+": failed to compare")
+	assert.Equal(t, "failed to compute the rawids of 1 of 3 inputs", errFiles.Error(),
+		"it should not count the file failed to compare twice")
+	require.ErrorIs(t, &errFiles, hasher.ErrRead)
+}
+
+//nolint:paralleltest // do not parallelize due to dependency on other tests
+func Test_main_golden_audit(t *testing.T) {
+	dirRoot := writeTree(t, map[string]string{
+		"x/a.txt": "abcdefgh",
+		"y/a.txt": "abcdefgh", // copies are not collisions
+		"f.txt":   "foo",
+		"e.txt":   "",
+	})
+
+	manifest := fmt.Sprintf("# comment\n351486625072160940  %s\n0  %s\n",
+		filepath.Join(dirRoot, "f.txt"), filepath.Join(dirRoot, "x/a.txt"))
+
+	for _, test := range []struct {
+		stdin  string
+		expect string
+		args   []string
+	}{
+		{
+			args:   []string{"audit", dirRoot},
+			expect: "audited 4 files of 3 distinct contents in 64-bit rawids\ncollisions: 0 observed, 1.63e-19 expected\n",
+		},
+		{
+			args:   []string{"audit", "--fast", dirRoot},
+			expect: "audited 4 files of 3 distinct contents in 64-bit rawids\ncollisions: 0 observed, 1.63e-19 expected\n",
+		},
+		{
+			// The rawids of 4 byte digests are of 32 bits
+			args:   []string{"audit", "--hash-len", "4", dirRoot},
+			expect: "audited 4 files of 3 distinct contents in 32-bit rawids\ncollisions: 0 observed, 6.98e-10 expected\n",
+		},
+		{
+			// The listed rawids are not used
+			args:   []string{"audit", "--from-manifest"},
+			stdin:  manifest,
+			expect: "audited 2 files of 2 distinct contents in 64-bit rawids\ncollisions: 0 observed, 5.42e-20 expected\n",
+		},
+		{
+			args:   []string{"audit", "--from-manifest", os.DevNull},
+			expect: "audited 0 files of 0 distinct contents in 64-bit rawids\ncollisions: 0 observed, 0 expected\n",
+		},
+	} {
+		deferRecover := setDummyArgs(t, test.args)
+		recoverStdin := mockSTDIN(t, test.stdin)
+
+		var stdout string
+
+		stderr := capturer.CaptureStderr(func() {
+			stdout = capturer.CaptureStdout(func() {
+				main()
+			})
+		})
+
+		recoverStdin()
+		deferRecover()
+
+		assert.Empty(t, stdout, "args: %v", test.args)
+		assert.Equal(t, test.expect, stderr, "args: %v", test.args)
+	}
+}
+
+//nolint:paralleltest // do not parallelize due to dependency on other tests
+func Test_main_audit_error(t *testing.T) {
+	for _, test := range []struct {
+		expect string
+		args   []string
+		status int
+	}{
+		{
+			args:   []string{"audit"},
+			expect: "missing paths. usage: genrawid audit [flags] <path>...",
+			status: ExitFailure,
+		},
+		{
+			args:   []string{"audit", "-"},
+			expect: "STDIN can not be used with audit",
+			status: ExitFailure,
+		},
+		{
+			args:   []string{"audit", "--lines", "../../testdata"},
+			expect: "--lines option can not be used with audit",
+			status: ExitFailure,
+		},
+		{
+			args:   []string{"audit", "--from-manifest", "../../testdata/unknown.txt"},
+			expect: "failed to open manifest",
+			status: ExitFailure,
+		},
+		{
+			args:   []string{"audit", "../../testdata/unknown.txt", "../../testdata/msg.txt"},
+			expect: "../../testdata/unknown.txt: failed to read from file",
+			status: ExitFailure,
+		},
+	} {
+		recoverArgs := setDummyArgs(t, test.args)
+
+		// Mock os.Exit to capture exit status
+		var status int
+
+		recoverOsExit := captureExitStatus(t, &status)
+
+		// Capture error
+		out := capturer.CaptureStderr(func() {
+			main()
+		})
+
+		recoverOsExit()
+		recoverArgs()
+
+		assert.Equal(t, test.status, status, "args: %v", test.args)
+		assert.Contains(t, out, test.expect, "args: %v", test.args)
+	}
+}
+
+//nolint:paralleltest // do not parallelize due to dependency on other tests
+func Test_main_audit_manifest_missing(t *testing.T) {
+	// The rest of the manifests are audited
+	recoverArgs := setDummyArgs(t, []string{
+		"audit",
+		"--from-manifest",
+		"../../testdata/unknown.txt",
+		writeManifest(t, "0  ../../testdata/msg.txt\n"),
+	})
+	defer recoverArgs()
+
+	// Mock os.Exit to capture exit status
+	var status int
+
+	recoverOsExit := captureExitStatus(t, &status)
+	defer recoverOsExit()
+
+	out := capturer.CaptureStderr(func() {
+		main()
+	})
+
+	assert.Equal(t, ExitFailure, status)
+	assert.Contains(t, out, "../../testdata/unknown.txt: failed to open manifest")
+	assert.Contains(t, out, "audited 1 file of 1 distinct content in 64-bit rawids")
+	assert.Contains(t, out, "failed to compute the rawids of 1 of 2 inputs")
+}
+
+//nolint:paralleltest // do not parallelize due to dependency on other tests
+func Test_main_audit_collision(t *testing.T) {
+	// The digests of BLAKE3 ignore the line breaks, so they share a rawid
+	dirRoot := writeTree(t, map[string]string{
+		"a.txt": "ab\n\n",
+		"b.txt": "a\nb\n",
+		"c.txt": "a\nb",
+		"d.txt": "ab",
+		"e.txt": "ab", // copies are not collisions
+	})
+
+	recoverArgs := setDummyArgs(t, []string{"audit", dirRoot})
+
+	// Mock os.Exit to capture exit status
+	var (
+		status int
+		stdout string
+	)
+
+	recoverOsExit := captureExitStatus(t, &status)
+
+	stderr := capturer.CaptureStderr(func() {
+		stdout = capturer.CaptureStdout(func() {
+			main()
+		})
+	})
+
+	recoverOsExit()
+	recoverArgs()
+
+	assert.Equal(t, ExitMismatch, status)
+	assert.Contains(t, stderr, "audited 5 files of 4 distinct contents in 64-bit rawids\ncollisions: 6 observed,")
+	assert.Contains(t, stderr, "found 6 pairs sharing a rawid with different contents: the rawids collided")
+	assert.NotContains(t, stderr, "did not match", "it should not be reported as a mismatch")
+
+	lines := strings.Split(strings.ReplaceAll(stdout, dirRoot+string(filepath.Separator), ""), "\n")
+
+	require.Len(t, lines, 6, "5 files and the last line break")
+
+	for i, path := range []string{"a.txt", "b.txt", "c.txt", "d.txt", "e.txt"} {
+		assert.True(t, strings.HasSuffix(lines[i], "  "+path), "line %d: %s", i, lines[i])
+	}
+}
+
+func Test_auditFiles(t *testing.T) {
+	t.Parallel()
+
+	dirRoot := writeTree(t, map[string]string{
+		"a": "abcdefgh",
+		"b": "abcdefgh",
+		"c": "abcdefgX",
+		"d": "foo",
+	})
+
+	newFile := func(path string, id, digest string) *dupe {
+		return &dupe{
+			path:   filepath.Join(dirRoot, path),
+			result: genrawid.Result{ID: []byte(id), Digest: []byte(digest)},
+		}
+	}
+
+	// The same rawid of different digests is a collision
+	collisions, stats := auditFiles([]*dupe{
+		newFile("d", "id-1----", "digest-2"),
+		newFile("b", "id-1----", "digest-1"),
+		newFile("a", "id-1----", "digest-1"),
+		newFile("c", "id-2----", "digest-3"),
+	}, new(filesError))
+
+	require.Len(t, collisions, 1)
+	require.Len(t, collisions[0], 2)
+	assert.Equal(t, filepath.Join(dirRoot, "a"), collisions[0][0][0].path, "the files should be sorted")
+	assert.Equal(t, filepath.Join(dirRoot, "b"), collisions[0][0][1].path)
+	assert.Equal(t, filepath.Join(dirRoot, "d"), collisions[0][1][0].path)
+	assert.Equal(t, auditStats{numCollisions: 1, numFiles: 4, numContents: 3, numBits: 64}, stats)
+
+	// The same rawid and digest of different contents is a collision byte by byte,
+	// such as of the line breaks or in fast mode
+	collisions, stats = auditFiles([]*dupe{
+		newFile("a", "id-1----", "id-1----"),
+		newFile("b", "id-1----", "id-1----"),
+		newFile("c", "id-1----", "id-1----"),
+		newFile("d", "id-1----", "id-1----"),
+	}, new(filesError))
+
+	require.Len(t, collisions, 1)
+	assert.Len(t, collisions[0], 3, "a and b should be the same content")
+	assert.Equal(t, int64(3), stats.numCollisions, "every pair of the 3 contents should be a collision")
+
+	// The bits are of the shortest digest
+	_, stats = auditFiles([]*dupe{newFile("a", "id-1----", "dige")}, new(filesError))
+
+	assert.Equal(t, 32, stats.numBits)
+	assert.InDelta(t, 0, stats.expected(), 0)
+}
 
 //nolint:paralleltest // do not parallelize due to dependency on other tests
-func Test_main_golden_base62(t *testing.T) {
-	// Set args
-	deferRecover := setDummyArgs(t, []string{
-		"--base62",
-		"../../testdata/msg.txt",
+func Test_main_golden_recursive_symlinks(t *testing.T) {
+	dirRoot := writeTree(t, map[string]string{
+		"a/x.txt": "abcdefgh",
 	})
+
+	if err := os.Symlink(filepath.Join(dirRoot, "a"), filepath.Join(dirRoot, "link")); err != nil {
+		t.Skip("symbolic links are not supported:", err)
+	}
+
+	for _, test := range []struct {
+		args   []string
+		expect []string
+	}{
+		{args: []string{"-r", dirRoot}, expect: []string{"a/x.txt"}},
+		{args: []string{"-r", "-L", dirRoot}, expect: []string{"a/x.txt", "link/x.txt"}},
+	} {
+		deferRecover := setDummyArgs(t, test.args)
+
+		out := capturer.CaptureStdout(func() {
+			main()
+		})
+
+		deferRecover()
+
+		expect := ""
+		for _, path := range test.expect {
+			expect += "-2474118025671277174  " + filepath.Join(dirRoot, path) + "\n"
+		}
+
+		assert.Equal(t, expect, out, "args: %v", test.args)
+	}
+}
+
+//nolint:paralleltest // do not parallelize due to dependency on other tests
+func Test_main_golden_format(t *testing.T) {
+	for _, test := range []struct {
+		expect string
+		args   []string
+	}{
+		{
+			args: []string{"--format", "json", "../../testdata/msg.txt"},
+			expect: util.HereDoc(`
+				[
+				{"path":"../../testdata/msg.txt","size":8,"dec":"-2474118025671277174","udec":"15972626048038274442","hex":"ddaa2ac39b79058a","base62":"j1UNoJA6ku6","scheme":"v1/blake3/512/crc32c","error":null}
+				]
+			`),
+		},
+		{
+			args: []string{"--format", "ndjson", "--fields", "path,hex", "../../testdata/msg.txt", "../../testdata/msg.txt"},
+			expect: util.HereDoc(`
+				{"path":"../../testdata/msg.txt","hex":"ddaa2ac39b79058a"}
+				{"path":"../../testdata/msg.txt","hex":"ddaa2ac39b79058a"}
+			`),
+		},
+		{
+			args: []string{"--format", "csv", "--fields", "base62, size, path", "../../testdata/msg.txt"},
+			expect: util.HereDoc(`
+				base62,size,path
+				j1UNoJA6ku6,8,../../testdata/msg.txt
+			`),
+		},
+		{
+			args:   []string{"--format", "tsv", "--fields", "dec,scheme", "--string", "abcdefgh"},
+			expect: "dec\tscheme\n-2474118025671277174\tv1/blake3/512/crc32c\n",
+		},
+		{
+			args: []string{"--format", "ndjson", "--fields", "scheme,dec", "--scheme", "default", "--scheme", "fast", "-s", "abcdefgh"},
+			expect: util.HereDoc(`
+				{"scheme":"v1/blake3/512/crc32c","dec":"-2474118025671277174"}
+				{"scheme":"fast-v2/xxh64","dec":"4238821247360054455"}
+			`),
+		},
+	} {
+		deferRecover := setDummyArgs(t, test.args)
+
+		out := capturer.CaptureStdout(func() {
+			main()
+		})
+
+		deferRecover()
+
+		assert.Equal(t, test.expect, out, "args: %v", test.args)
+	}
+}
+
+//nolint:paralleltest // do not parallelize due to dependency on other tests
+func Test_main_golden_help(t *testing.T) {
+	// Set args
+	deferRecover := setDummyArgs(t, []string{"-h"})
 	defer deferRecover()
 
-	out := capturer.CaptureStdout(func() {
+	out := capturer.CaptureStderr(func() {
 		main()
 	})
 
-	expect := "j1UNoJA6ku6"
-	actual := out
-	assert.Equal(t, expect, actual)
+	assert.Contains(t, out, "Usage:")
+	assert.Contains(t, out, "Flags:")
 }
 
 //nolint:paralleltest // do not parallelize due to dependency on other tests
-func Test_main_golden_file(t *testing.T) {
+func Test_main_golden_hex_and_newline(t *testing.T) {
 	// Set args
 	deferRecover := setDummyArgs(t, []string{
+		"--new-line",
+		"--hex",
 		"../../testdata/msg.txt",
 	})
 	defer deferRecover()
@@ -51,31 +1448,92 @@ func Test_main_golden_file(t *testing.T) {
 		main()
 	})
 
-	expect := "-2474118025671277174"
+	expect := "0xddaa2ac39b79058a\n"
 	actual := out
 	assert.Equal(t, expect, actual)
 }
 
 //nolint:paralleltest // do not parallelize due to dependency on other tests
-func Test_main_golden_help(t *testing.T) {
+func Test_main_golden_mode_fast(t *testing.T) {
 	// Set args
-	deferRecover := setDummyArgs(t, []string{"-h"})
+	deferRecover := setDummyArgs(t, []string{
+		"--fast",
+		"--hex",
+		"../../testdata/msg.txt",
+	})
 	defer deferRecover()
 
-	out := capturer.CaptureStderr(func() {
+	out := capturer.CaptureStdout(func() {
 		main()
 	})
 
-	assert.Contains(t, out, "Usage:")
-	assert.Contains(t, out, "Flags:")
+	expect := "0x3ad351775b4634b7"
+	actual := out
+	assert.Equal(t, expect, actual)
 }
 
 //nolint:paralleltest // do not parallelize due to dependency on other tests
-func Test_main_golden_hex_and_newline(t *testing.T) {
+func Test_main_golden_records(t *testing.T) {
+	for _, test := range []struct {
+		input  string
+		expect string
+		args   []string
+	}{
+		{
+			args:   []string{"--lines"},
+			input:  "abcdefgh\nfoo\n\nabcdefgh",
+			expect: "-2474118025671277174\n351486625072160940\n-5831236030863945657\n-2474118025671277174\n",
+		},
+		{
+			args:   []string{"--lines", "--with-record", "--strip-cr", "--hex", "--fast", "-"},
+			input:  "abcdefgh\r\nfoo\n",
+			expect: "0x3ad351775b4634b7  abcdefgh\n0x33bf00a859c4ba3f  foo\n",
+		},
+		{
+			// The "\r" is a part of the record without --strip-cr
+			args:   []string{"--lines", "--hex", "--fast"},
+			input:  "abcdefgh\r\nfoo\n",
+			expect: "0x3c9d1ee1abf4323e\n0x33bf00a859c4ba3f\n",
+		},
+		{
+			// BLAKE3 ignores the line breaks. It strips the printed records only
+			args:   []string{"--lines", "--with-record"},
+			input:  "abc\r\nabc\n",
+			expect: "7221438080733764522  abc\r\n7221438080733764522  abc\n",
+		},
+		{
+			args:   []string{"--lines", "--with-record", "--strip-cr"},
+			input:  "abc\r\nabc\n",
+			expect: "7221438080733764522  abc\n7221438080733764522  abc\n",
+		},
+		{
+			args:   []string{"-z", "--with-record"},
+			input:  "abcdefgh\x00foo\nbar\x00",
+			expect: "-2474118025671277174  abcdefgh\x00-6173910810199069570  foo\nbar\x00",
+		},
+	} {
+		deferRecover := setDummyArgs(t, test.args)
+		recoverStdin := mockSTDIN(t, test.input)
+
+		out := capturer.CaptureStdout(func() {
+			main()
+		})
+
+		recoverStdin()
+		deferRecover()
+
+		assert.Equal(t, test.expect, out, "args: %v", test.args)
+	}
+}
+
+//nolint:paralleltest // do not parallelize due to dependency on other tests
+func Test_main_golden_schemes(t *testing.T) {
 	// Set args
 	deferRecover := setDummyArgs(t, []string{
-		"--new-line",
 		"--hex",
+		"--scheme", "default",
+		"--scheme", "v1/sha3-512/512/crc32c",
+		"--scheme", "fast",
 		"../../testdata/msg.txt",
 	})
 	defer deferRecover()
@@ -84,18 +1542,20 @@ func Test_main_golden_hex_and_newline(t *testing.T) {
 		main()
 	})
 
-	expect := "0xddaa2ac39b79058a\n"
+	expect := "v1/blake3/512/crc32c 0xddaa2ac39b79058a\n" +
+		"v1/sha3-512/512/crc32c 0xc9f25eee4b129f67\n" +
+		"fast-v2/xxh64 0x3ad351775b4634b7\n"
 	actual := out
 	assert.Equal(t, expect, actual)
 }
 
 //nolint:paralleltest // do not parallelize due to dependency on other tests
-func Test_main_golden_mode_fast(t *testing.T) {
+func Test_main_golden_scheme_single(t *testing.T) {
 	// Set args
 	deferRecover := setDummyArgs(t, []string{
-		"--fast",
-		"--hex",
-		"../../testdata/msg.txt",
+		"--scheme", "fast",
+		"--string", "abcdefgh",
+		"--verify", "4238821247360054455",
 	})
 	defer deferRecover()
 
@@ -103,7 +1563,8 @@ func Test_main_golden_mode_fast(t *testing.T) {
 		main()
 	})
 
-	expect := "0xddaa2ac30a98963b"
+	// It should print the rawid only as usual
+	expect := "4238821247360054455"
 	actual := out
 	assert.Equal(t, expect, actual)
 }
@@ -162,6 +1623,36 @@ func Test_main_golden_verify(t *testing.T) {
 	assert.Equal(t, expect, actual)
 }
 
+//nolint:paralleltest // do not parallelize due to dependency on other tests
+func Test_main_golden_verify_any_representation(t *testing.T) {
+	for _, verify := range []string{
+		"-2474118025671277174", // signed decimal
+		"15972626048038274442", // unsigned decimal
+		"0xddaa2ac39b79058a",   // hex
+		"ddaa2ac39b79058a",     // hex without "0x"
+		"0xDDAA2AC39B79058A",   // upper-case hex
+		"DDAA2AC39B79058A",     // upper-case hex without "0x"
+		"j1UNoJA6ku6",          // Base62
+	} {
+		deferRecover := setDummyArgs(t, []string{"--hex", "-s", "abcdefgh", "--verify", verify})
+
+		// Mock os.Exit to capture exit status
+		status := -1
+
+		recoverOsExit := captureExitStatus(t, &status)
+
+		out := capturer.CaptureStdout(func() {
+			main()
+		})
+
+		recoverOsExit()
+		deferRecover()
+
+		assert.Equal(t, -1, status, "it should not exit. verify: %s", verify)
+		assert.Equal(t, "0xddaa2ac39b79058a", out, "verify: %s", verify)
+	}
+}
+
 // ----------------------------------------------------------------------------
 //  Error Cases
 // ----------------------------------------------------------------------------
@@ -183,10 +1674,577 @@ func Test_main_missing_args(t *testing.T) {
 		main()
 	})
 
-	assert.Equal(t, 1, status, "it should exit with status 1 on error")
+	assert.Equal(t, ExitFailure, status, "it should exit with status 2 on error")
 	assert.Contains(t, out, "error: missing arguments")
 }
 
+//nolint:paralleltest // do not parallelize due to dependency on other tests
+func Test_main_bad_flag(t *testing.T) {
+	recoverArgs := setDummyArgs(t, []string{"--unknown-flag", "-s", "foo"})
+	defer recoverArgs()
+
+	// Mock os.Exit to capture exit status
+	var status int
+
+	recoverOsExit := captureExitStatus(t, &status)
+	defer recoverOsExit()
+
+	// Capture error
+	out := capturer.CaptureStderr(func() {
+		main()
+	})
+
+	assert.Equal(t, ExitFailure, status, "it should exit with status 2 on bad flag")
+	assert.Contains(t, out, "invalid flags: unknown flag: --unknown-flag")
+}
+
+//nolint:paralleltest // do not parallelize due to dependency on other tests
+func Test_main_unknown_checksum(t *testing.T) {
+	// Set args and defer recover
+	recoverArgs := setDummyArgs(t, []string{
+		"--checksum",
+		"md5",
+		"../../testdata/msg.txt",
+	})
+	defer recoverArgs()
+
+	// Mock os.Exit to capture exit status
+	var status int
+
+	recoverOsExit := captureExitStatus(t, &status)
+	defer recoverOsExit()
+
+	// Capture error
+	out := capturer.CaptureStderr(func() {
+		main()
+	})
+
+	assert.Equal(t, ExitUnknownAlgo, status, "it should exit with status 3 on unknown algorithm")
+	assert.Contains(t, out, "invalid --checksum option")
+	assert.Contains(t, out, "unknown checksum algorithm: md5")
+}
+
+//nolint:paralleltest // do not parallelize due to dependency on other tests
+func Test_main_golden_default_poly(t *testing.T) {
+	// The default polynomial in other forms is not taken as changed
+	for _, test := range []struct {
+		expect string
+		args   []string
+	}{
+		{
+			args:   []string{"--fast", "--crc-poly", "Castagnoli"},
+			expect: "4238821247360054455",
+		},
+		{
+			args:   []string{"--fast", "--crc-poly", "0x82F63B78"},
+			expect: "4238821247360054455",
+		},
+		{
+			args:   []string{"--hex", "--checksum", "xor8", "--crc-poly", "0x82f63b78"},
+			expect: "0xddaa2ac30a98651b",
+		},
+	} {
+		deferRecover := setDummyArgs(t, append(test.args, "../../testdata/msg.txt"))
+
+		out := capturer.CaptureStdout(func() {
+			main()
+		})
+
+		deferRecover()
+
+		assert.Equal(t, test.expect, out, "args: %v", test.args)
+	}
+}
+
+//nolint:paralleltest // do not parallelize due to dependency on other tests
+func Test_main_algorithm_error(t *testing.T) {
+	for _, test := range []struct {
+		expect string
+		args   []string
+		status int
+	}{
+		{
+			args:   []string{"--hash", "md5"},
+			expect: "invalid --hash option: unknown hash algorithm: md5",
+			status: ExitUnknownAlgo,
+		},
+		{
+			args:   []string{"--hash", "sha3-512", "--hash-len", "65"},
+			expect: "invalid --hash-len option",
+			status: ExitInvalidLength,
+		},
+		{
+			args:   []string{"--hash-len", "2"},
+			expect: "invalid output length",
+			status: ExitInvalidLength,
+		},
+		{
+			// Too short to fill the rawid with the 1 byte checksum
+			args:   []string{"--hash-len", "6", "--checksum", "xor8"},
+			expect: "invalid --hash-len option: invalid output length. It must be 7 or more",
+			status: ExitInvalidLength,
+		},
+		{
+			// Validated before reading the input
+			args:   []string{"--hash-len", "2", "../../testdata/unknown.txt"},
+			expect: "invalid --hash-len option",
+			status: ExitInvalidLength,
+		},
+		{
+			args:   []string{"--crc-poly", "foo"},
+			expect: "invalid --crc-poly option: unknown polynomial: foo",
+			status: ExitFailure,
+		},
+		{
+			args:   []string{"--crc-poly", "0x0"},
+			expect: "invalid --crc-poly option: invalid polynomial: 0x0",
+			status: ExitFailure,
+		},
+		{
+			args:   []string{"--crc-poly", "ieee", "--checksum", "xor8"},
+			expect: "--crc-poly option is meaningful only with crc32 checksum",
+			status: ExitFailure,
+		},
+		{
+			args:   []string{"--hash", "sha3-512", "--fast"},
+			expect: "options can not be used with --fast option",
+			status: ExitFailure,
+		},
+		{
+			args:   []string{"--hash", "sha3-512", "--scheme", "default"},
+			expect: "--scheme option can not be used with --fast, --context or the algorithm options",
+			status: ExitFailure,
+		},
+	} {
+		recoverArgs := setDummyArgs(t, append(test.args, "../../testdata/msg.txt"))
+
+		// Mock os.Exit to capture exit status
+		var status int
+
+		recoverOsExit := captureExitStatus(t, &status)
+
+		// Capture error
+		out := capturer.CaptureStderr(func() {
+			main()
+		})
+
+		recoverOsExit()
+		recoverArgs()
+
+		assert.Equal(t, test.status, status, "args: %v", test.args)
+		assert.Contains(t, out, test.expect, "args: %v", test.args)
+	}
+}
+
+//nolint:paralleltest // do not parallelize due to dependency on other tests
+func Test_main_records_option_error(t *testing.T) {
+	for _, test := range []struct {
+		expect string
+		args   []string
+	}{
+		{
+			args:   []string{"--with-record", "-s", "foo"},
+			expect: "--strip-cr and --with-record options are meaningful only with --lines or --null option",
+		},
+		{
+			args:   []string{"--lines", "-z"},
+			expect: "--lines and --null options can not be used together",
+		},
+		{
+			args:   []string{"-z", "--strip-cr"},
+			expect: "--strip-cr option is meaningful only with --lines option",
+		},
+		{
+			args:   []string{"--lines", "--verify", "1"},
+			expect: "--lines and --null options can not be used with --verify option",
+		},
+		{
+			args:   []string{"--lines", "--format", "json"},
+			expect: "--lines and --null options can not be used with --format option",
+		},
+		{
+			args:   []string{"--lines", "../../testdata/msg.txt", "../../testdata/msg.txt"},
+			expect: "--lines and --null options can not be used with more than one input",
+		},
+	} {
+		recoverArgs := setDummyArgs(t, test.args)
+
+		// Mock os.Exit to capture exit status
+		var status int
+
+		recoverOsExit := captureExitStatus(t, &status)
+
+		// Capture error
+		out := capturer.CaptureStderr(func() {
+			main()
+		})
+
+		recoverOsExit()
+		recoverArgs()
+
+		assert.Equal(t, ExitFailure, status, "args: %v", test.args)
+		assert.Contains(t, out, test.expect, "args: %v", test.args)
+	}
+}
+
+//nolint:paralleltest // do not parallelize due to dependency on other tests
+func Test_main_config_error(t *testing.T) {
+	pathConfig := filepath.Join(t.TempDir(), "config")
+	require.NoError(t, os.WriteFile(pathConfig, []byte("# comment\nstring = foo\n"), 0o600))
+
+	for _, test := range []struct {
+		env    map[string]string
+		expect string
+		args   []string
+		status int
+	}{
+		{
+			args:   []string{"--config", pathConfig, "-s", "foo"},
+			expect: "invalid config file " + pathConfig + ":2: unknown key: string",
+			status: ExitFailure,
+		},
+		{
+			args:   []string{"--config", "../../testdata/unknown.txt", "-s", "foo"},
+			expect: "failed to open config file",
+			status: ExitFailure,
+		},
+		{
+			args:   []string{"-s", "foo"},
+			env:    map[string]string{"GENRAWID_HASH_LEN": "foo"},
+			expect: "invalid hash-len of env GENRAWID_HASH_LEN",
+			status: ExitFailure,
+		},
+		{
+			args:   []string{"-s", "foo"},
+			env:    map[string]string{"GENRAWID_HASH": "md5"},
+			expect: "unknown hash algorithm: md5",
+			status: ExitUnknownAlgo,
+		},
+		{
+			args:   []string{"config", "edit"},
+			expect: "unknown config command",
+			status: ExitFailure,
+		},
+		{
+			args:   []string{"completion", "powershell"},
+			expect: "unsupported shell: powershell",
+			status: ExitFailure,
+		},
+		{
+			args:   []string{"completion"},
+			expect: "usage: genrawid completion bash|zsh|fish",
+			status: ExitFailure,
+		},
+	} {
+		recoverArgs := setDummyArgs(t, test.args)
+		recoverEnv := setEnvs(t, test.env)
+
+		// Mock os.Exit to capture exit status
+		var status int
+
+		recoverOsExit := captureExitStatus(t, &status)
+
+		// Capture error
+		out := capturer.CaptureStderr(func() {
+			main()
+		})
+
+		recoverOsExit()
+		recoverEnv()
+		recoverArgs()
+
+		assert.Equal(t, test.status, status, "args: %v", test.args)
+		assert.Contains(t, out, test.expect, "args: %v", test.args)
+	}
+}
+
+//nolint:paralleltest // do not parallelize due to dependency on other tests
+func Test_main_convert_error(t *testing.T) {
+	for _, test := range []struct {
+		expect string
+		stdout string
+		args   []string
+		status int
+	}{
+		{
+			args:   []string{"--to", "dec", "j1UNoJA6ku6", "foo!"},
+			expect: "arg 2: invalid rawid: fail to decode Base62 input: foo!. invalid character at position 3",
+			stdout: "-2474118025671277174\n\n",
+			status: ExitInvalidRawid,
+		},
+		{
+			args:   []string{"--to", "dec", "--from", "udec", "-1"},
+			expect: "fail to decode unsigned decimal input: -1. invalid character at position 0",
+			stdout: "\n",
+			status: ExitInvalidRawid,
+		},
+		{
+			args:   []string{"--to", "dec", "lYGhA16ahyg"},
+			expect: "has more than 8 bytes after decoding",
+			stdout: "\n",
+			status: ExitInvalidRawid,
+		},
+		{
+			args:   []string{"1"},
+			expect: "missing --to option",
+			status: ExitFailure,
+		},
+		{
+			args:   []string{"--to", "oct", "1"},
+			expect: "invalid --to option: oct",
+			status: ExitFailure,
+		},
+		{
+			args:   []string{"--to", "dec", "--from", "oct", "1"},
+			expect: "invalid --from option: oct",
+			status: ExitFailure,
+		},
+		{
+			args:   []string{"--unknown"},
+			expect: "invalid arguments of convert",
+			status: ExitFailure,
+		},
+	} {
+		recoverArgs := setDummyArgs(t, append([]string{"convert"}, test.args...))
+
+		// Mock os.Exit to capture exit status
+		var status int
+
+		recoverOsExit := captureExitStatus(t, &status)
+
+		// Capture both outputs
+		var stdout string
+
+		stderr := capturer.CaptureStderr(func() {
+			stdout = capturer.CaptureStdout(func() {
+				main()
+			})
+		})
+
+		recoverOsExit()
+		recoverArgs()
+
+		assert.Equal(t, test.status, status, "args: %v", test.args)
+		assert.Equal(t, test.stdout, stdout, "args: %v", test.args)
+		assert.Contains(t, stderr, test.expect, "args: %v", test.args)
+	}
+}
+
+//nolint:paralleltest // do not parallelize due to dependency on other tests
+func Test_main_scheme_error(t *testing.T) {
+	for _, test := range []struct {
+		expect string
+		args   []string
+		status int
+	}{
+		{
+			args:   []string{"--scheme", "v1/md5/128/crc32c", "-s", "foo"},
+			expect: "invalid --scheme option",
+			status: ExitUnknownAlgo,
+		},
+		{
+			args:   []string{"--scheme", "default", "--fast", "-s", "foo"},
+			expect: "--scheme option can not be used with --fast, --context or the algorithm options",
+			status: ExitFailure,
+		},
+		{
+			args:   []string{"--scheme", "default", "--checksum", "xor8", "-s", "foo"},
+			expect: "--scheme option can not be used with --fast, --context or the algorithm options",
+			status: ExitFailure,
+		},
+		{
+			args:   []string{"--scheme", "default", "--context", "foo", "-s", "foo"},
+			expect: "--scheme option can not be used with --fast, --context or the algorithm options",
+			status: ExitFailure,
+		},
+		{
+			args:   []string{"--scheme", "default", "--scheme", "fast", "--verify", "1", "-s", "foo"},
+			expect: "--verify option can not be used with more than one --scheme option",
+			status: ExitFailure,
+		},
+		{
+			args:   []string{"--scheme", "default", "--scheme", "fast", "../../testdata/unknown.txt"},
+			expect: "failed to read from file",
+			status: ExitFailure,
+		},
+	} {
+		recoverArgs := setDummyArgs(t, test.args)
+
+		// Mock os.Exit to capture exit status
+		var status int
+
+		recoverOsExit := captureExitStatus(t, &status)
+
+		// Capture error
+		out := capturer.CaptureStderr(func() {
+			main()
+		})
+
+		recoverOsExit()
+		recoverArgs()
+
+		assert.Equal(t, test.status, status, "args: %v", test.args)
+		assert.Contains(t, out, test.expect, "args: %v", test.args)
+	}
+}
+
+//nolint:paralleltest // do not parallelize due to dependency on other tests
+func Test_main_check_error(t *testing.T) {
+	lineOK := "-2474118025671277174  ../../testdata/msg.txt\n"
+	lineFailed := "0x0000000000000001  ../../testdata/msg.txt\n"
+	lineMissing := "-2474118025671277174  ../../testdata/unknown.txt\n"
+	lineInvalid := "-2474118025671277174 ../../testdata/msg.txt\n"
+
+	for _, test := range []struct {
+		manifest string
+		contains []string
+		status   int
+	}{
+		{
+			manifest: lineOK + lineFailed + lineMissing + lineInvalid,
+			status:   ExitMismatch,
+			contains: []string{
+				"../../testdata/msg.txt: OK",
+				"../../testdata/msg.txt: FAILED",
+				"../../testdata/unknown.txt: MISSING",
+				":4: improperly formatted line",
+				"WARNING: 1 computed rawid did NOT match",
+				"WARNING: 1 listed file could not be read",
+				"WARNING: 1 line is improperly formatted",
+			},
+		},
+		{
+			manifest: lineOK + lineMissing + lineMissing,
+			status:   ExitFailure,
+			contains: []string{"WARNING: 2 listed files could not be read"},
+		},
+		{
+			manifest: lineOK + "abc&  ../../testdata/msg.txt\n",
+			status:   ExitInvalidRawid,
+			contains: []string{"invalid rawid: fail to decode Base62 input: abc&"},
+		},
+	} {
+		deferRecover := setDummyArgs(t, []string{"--check", writeManifest(t, test.manifest)})
+
+		// Mock os.Exit to capture exit status
+		var status int
+
+		recoverOsExit := captureExitStatus(t, &status)
+
+		out := capturer.CaptureOutput(func() {
+			main()
+		})
+
+		recoverOsExit()
+		deferRecover()
+
+		assert.Equal(t, test.status, status, "manifest: %s", test.manifest)
+
+		for _, contains := range test.contains {
+			assert.Contains(t, out, contains)
+		}
+
+		assert.NotContains(t, out, "verification failed", "the summary error should not be printed")
+	}
+}
+
+//nolint:paralleltest // do not parallelize due to dependency on other tests
+func Test_main_check_manifest_missing(t *testing.T) {
+	// The rest of the manifests are checked, like sha256sum
+	deferRecover := setDummyArgs(t, []string{
+		"--check",
+		"../../testdata/unknown.txt",
+		writeManifest(t, "-2474118025671277174  ../../testdata/msg.txt\n"),
+	})
+	defer deferRecover()
+
+	// Mock os.Exit to capture exit status
+	var status int
+
+	recoverOsExit := captureExitStatus(t, &status)
+	defer recoverOsExit()
+
+	out := capturer.CaptureOutput(func() {
+		main()
+	})
+
+	assert.Equal(t, ExitFailure, status)
+	assert.Contains(t, out, "../../testdata/unknown.txt: failed to open manifest")
+	assert.Contains(t, out, "../../testdata/unknown.txt: MISSING")
+	assert.Contains(t, out, "../../testdata/msg.txt: OK")
+	assert.Contains(t, out, "WARNING: 1 listed file could not be read")
+}
+
+//nolint:paralleltest // do not parallelize due to dependency on other tests
+func Test_main_check_status(t *testing.T) {
+	deferRecover := setDummyArgs(t, []string{
+		"--check",
+		"--status",
+		writeManifest(t, "0x0000000000000001  ../../testdata/msg.txt\n"),
+	})
+	defer deferRecover()
+
+	// Mock os.Exit to capture exit status
+	var status int
+
+	recoverOsExit := captureExitStatus(t, &status)
+	defer recoverOsExit()
+
+	out := capturer.CaptureOutput(func() {
+		main()
+	})
+
+	assert.Equal(t, ExitMismatch, status)
+	assert.Equal(t, "exit status: 1\n", out, "it should print nothing but the mocked exit status")
+}
+
+//nolint:paralleltest // do not parallelize due to dependency on other tests
+func Test_main_check_option_error(t *testing.T) {
+	for _, test := range []struct {
+		expect string
+		args   []string
+	}{
+		{
+			args:   []string{"--quiet", "../../testdata/msg.txt"},
+			expect: "--quiet and --status options are meaningful only with --check option",
+		},
+		{
+			args:   []string{"--check", "-s", "foo"},
+			expect: "--string option can not be used with --check option",
+		},
+		{
+			args:   []string{"--check", "--verify", "1"},
+			expect: "--verify option can not be used with --check option",
+		},
+		{
+			args:   []string{"--check", "--scheme", "default"},
+			expect: "--scheme option can not be used with --check option",
+		},
+		{
+			args:   []string{"--check", "../../testdata/unknown.txt"},
+			expect: "failed to open manifest",
+		},
+	} {
+		recoverArgs := setDummyArgs(t, test.args)
+
+		// Mock os.Exit to capture exit status
+		var status int
+
+		recoverOsExit := captureExitStatus(t, &status)
+
+		// Capture error
+		out := capturer.CaptureStderr(func() {
+			main()
+		})
+
+		recoverOsExit()
+		recoverArgs()
+
+		assert.NotEqual(t, 0, status, "args: %v", test.args)
+		assert.Contains(t, out, test.expect, "args: %v", test.args)
+	}
+}
+
 //nolint:paralleltest // do not parallelize due to dependency on other tests
 func Test_main_path_was_dir(t *testing.T) {
 	// Set empty args and defer recover
@@ -206,9 +2264,30 @@ func Test_main_path_was_dir(t *testing.T) {
 		main()
 	})
 
-	assert.Equal(t, 1, status, "it should exit with status 1 on error")
+	assert.Equal(t, ExitFailure, status, "it should exit with status 2 on read error")
 	assert.Contains(t, out, "failed to read from file")
-	assert.Contains(t, out, "failed to read during scanning")
+	assert.Contains(t, out, "failed to read input")
+}
+
+//nolint:paralleltest // do not parallelize due to dependency on other tests
+func Test_main_verify_invalid_base62(t *testing.T) {
+	recoverArgs := setDummyArgs(t, []string{"-s", "foo", "--base62", "--verify", "abc&"})
+	defer recoverArgs()
+
+	// Mock os.Exit to capture exit status
+	var status int
+
+	recoverOsExit := captureExitStatus(t, &status)
+	defer recoverOsExit()
+
+	// Capture error
+	out := capturer.CaptureStderr(func() {
+		main()
+	})
+
+	assert.Equal(t, ExitInvalidRawid, status, "it should exit with status 5 on invalid rawid")
+	assert.Contains(t, out, "invalid --verify option")
+	assert.Contains(t, out, "invalid character at position 3")
 }
 
 //nolint:paralleltest // do not parallelize due to dependency on other tests
@@ -239,17 +2318,17 @@ func Test_main_stdin_error(t *testing.T) {
 		main()
 	})
 
-	assert.Equal(t, 1, status, "it should exit with status 1 on error")
+	assert.Equal(t, ExitFailure, status, "it should exit with status 2 on read error")
 
 	assert.Contains(t, out, "failed to read from STDIN")
-	assert.Contains(t, out, "failed to read during scanning")
+	assert.Contains(t, out, "failed to read input")
 }
 
 //nolint:paralleltest // do not parallelize due to dependency on other tests
-func Test_main_too_many_args(t *testing.T) {
-	// Set empty args and defer recover
+func Test_main_files_error(t *testing.T) {
 	recoverArgs := setDummyArgs(t, []string{
 		"../../testdata/msg.txt",
+		"../../testdata/unknown.txt",
 		"../../testdata/msg.txt",
 	})
 	defer recoverArgs()
@@ -260,15 +2339,163 @@ func Test_main_too_many_args(t *testing.T) {
 	recoverOsExit := captureExitStatus(t, &status)
 	defer recoverOsExit()
 
-	// Capture error
-	out := capturer.CaptureStderr(func() {
-		main()
+	// Capture both outputs
+	var stdout string
+
+	stderr := capturer.CaptureStderr(func() {
+		stdout = capturer.CaptureStdout(func() {
+			main()
+		})
+	})
+
+	// It should continue past the unreadable file
+	expect := util.HereDoc(`
+		-2474118025671277174  ../../testdata/msg.txt
+		-2474118025671277174  ../../testdata/msg.txt
+	`)
+	assert.Equal(t, expect, stdout)
+
+	assert.Equal(t, ExitFailure, status, "it should exit with status 2 on read error")
+	assert.Contains(t, stderr, "../../testdata/unknown.txt: failed to read from file")
+	assert.Contains(t, stderr, "failed to compute the rawids of 1 of 3 inputs")
+}
+
+//nolint:paralleltest // do not parallelize due to dependency on other tests
+func Test_main_format_error(t *testing.T) {
+	recoverArgs := setDummyArgs(t, []string{
+		"--format", "json", "--fields", "path,dec,error",
+		"../../testdata/msg.txt",
+		"../../testdata/unknown.txt",
+	})
+	defer recoverArgs()
+
+	// Mock os.Exit to capture exit status
+	var status int
+
+	recoverOsExit := captureExitStatus(t, &status)
+	defer recoverOsExit()
+
+	// Capture both outputs
+	var stdout string
+
+	stderr := capturer.CaptureStderr(func() {
+		stdout = capturer.CaptureStdout(func() {
+			main()
+		})
 	})
 
-	assert.Equal(t, 1, status, "it should exit with status 1 on error")
+	// The failed input should be a record with the error
+	var records []map[string]interface{}
+
+	require.NoError(t, json.Unmarshal([]byte(stdout), &records))
+	require.Len(t, records, 2)
+
+	assert.Equal(t, "-2474118025671277174", records[0]["dec"])
+	assert.Nil(t, records[0]["error"])
+	assert.Equal(t, "../../testdata/unknown.txt", records[1]["path"])
+	assert.Nil(t, records[1]["dec"])
+	assert.Contains(t, records[1]["error"], "failed to read from file")
+
+	assert.Equal(t, ExitFailure, status, "it should exit with status 2 on read error")
+	assert.Contains(t, stderr, "failed to compute the rawids of 1 of 2 inputs")
+}
+
+//nolint:paralleltest // do not parallelize due to dependency on other tests
+func Test_main_format_option_error(t *testing.T) {
+	for _, test := range []struct {
+		expect string
+		args   []string
+	}{
+		{
+			args:   []string{"--format", "xml", "../../testdata/msg.txt"},
+			expect: "invalid --format option: xml",
+		},
+		{
+			args:   []string{"--format", "csv", "--fields", "path,md5", "../../testdata/msg.txt"},
+			expect: "unknown field: md5",
+		},
+		{
+			args:   []string{"--fields", "path", "../../testdata/msg.txt"},
+			expect: "--fields option is meaningful only with --format option",
+		},
+		{
+			args:   []string{"--format", "json", "--check", "../../testdata/msg.txt"},
+			expect: "--format option can not be used with --check option",
+		},
+		{
+			args:   []string{"--format", "json", "--verify", "1", "../../testdata/msg.txt"},
+			expect: "--format option can not be used with --verify option",
+		},
+	} {
+		recoverArgs := setDummyArgs(t, test.args)
+
+		// Mock os.Exit to capture exit status
+		var status int
+
+		recoverOsExit := captureExitStatus(t, &status)
+
+		// Capture error
+		out := capturer.CaptureStderr(func() {
+			main()
+		})
+
+		recoverOsExit()
+		recoverArgs()
+
+		assert.Equal(t, ExitFailure, status, "args: %v", test.args)
+		assert.Contains(t, out, test.expect, "args: %v", test.args)
+	}
+}
 
-	contains := "too many arguments. more than one file path given"
-	assert.Contains(t, out, contains)
+//nolint:paralleltest // do not parallelize due to dependency on other tests
+func Test_main_files_option_error(t *testing.T) {
+	for _, test := range []struct {
+		expect string
+		args   []string
+	}{
+		{
+			args:   []string{"-s", "foo", "../../testdata/msg.txt", "../../testdata/msg.txt"},
+			expect: "--string option can not be used with file arguments",
+		},
+		{
+			args:   []string{"--verify", "1", "../../testdata/msg.txt", "../../testdata/msg.txt"},
+			expect: "--verify option can not be used with more than one input",
+		},
+		{
+			args:   []string{"--scheme", "default", "../../testdata/msg.txt", "../../testdata/msg.txt"},
+			expect: "--scheme option can not be used with more than one input",
+		},
+		{
+			args:   []string{"--exclude", "*.tmp", "../../testdata/msg.txt"},
+			expect: "options are meaningful only with --recursive option",
+		},
+		{
+			args:   []string{"-r", "--exclude", "[", "../../testdata"},
+			expect: "invalid pattern: [",
+		},
+		{
+			args:   []string{"-r", "--ignore-file", "../../testdata/unknown.txt", "../../testdata"},
+			expect: "failed to open ignore file",
+		},
+	} {
+		recoverArgs := setDummyArgs(t, test.args)
+
+		// Mock os.Exit to capture exit status
+		var status int
+
+		recoverOsExit := captureExitStatus(t, &status)
+
+		// Capture error
+		out := capturer.CaptureStderr(func() {
+			main()
+		})
+
+		recoverOsExit()
+		recoverArgs()
+
+		assert.NotEqual(t, 0, status, "args: %v", test.args)
+		assert.Contains(t, out, test.expect, "args: %v", test.args)
+	}
 }
 
 //nolint:paralleltest // do not parallelize due to dependency on other tests
@@ -293,10 +2520,52 @@ func Test_main_verify_error(t *testing.T) {
 		main()
 	})
 
-	assert.Equal(t, 1, status, "it should exit with status 1 on error")
+	assert.Equal(t, ExitMismatch, status, "it should exit with status 1 on mismatch")
 	assert.Contains(t, out, "the two rawids did not match")
 }
 
+func Test_expandGlobs(t *testing.T) {
+	t.Parallel()
+
+	actual := expandGlobs([]string{
+		"-",
+		"../../testdata/m*.txt",    // expanded
+		"../../testdata/none*.txt", // matches nothing
+		"../../testdata/[.txt",     // invalid pattern
+	})
+
+	expect := []string{
+		"-",
+		"../../testdata/msg.txt",
+		"../../testdata/none*.txt",
+		"../../testdata/[.txt",
+	}
+	assert.Equal(t, expect, actual)
+}
+
+func TestExitStatus(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		err    error
+		expect int
+	}{
+		{nil, 0},
+		{errors.New("foo"), ExitFailure},
+		{errors.Wrap(errMismatch, "bar"), ExitMismatch},
+		{&checkError{kind: errMismatch}, ExitMismatch},
+		{errors.Wrap(errCollision, "bar"), ExitMismatch},
+		{errors.Wrap(hasher.NewErrRead(errors.New("foo")), "bar"), ExitFailure},
+		{errors.Wrap(errors.New("unknown flag: --foo"), "invalid flags"), ExitFailure},
+		{errors.Wrap(hasher.ErrUnknownAlgorithm, "bar"), ExitUnknownAlgo},
+		{errors.Wrap(&hasher.ErrInvalidLength{Given: 2, Min: 4}, "bar"), ExitInvalidLength},
+		{errors.Wrap(&rawid.ErrDecode{Input: "&"}, "bar"), ExitInvalidRawid},
+		{errors.Wrap(rawid.ErrOverRange, "bar"), ExitInvalidRawid},
+	} {
+		assert.Equal(t, test.expect, ExitStatus(test.err), "error: %v", test.err)
+	}
+}
+
 // ============================================================================
 //  Helper Functions
 // ============================================================================
@@ -316,6 +2585,35 @@ func captureExitStatus(t *testing.T, status *int) func() {
 	}
 }
 
+// It writes the manifest for --check option to a temporary file and returns its
+// path.
+func writeManifest(t *testing.T, manifest string) string {
+	t.Helper()
+
+	pathManifest := filepath.Join(t.TempDir(), "manifest.txt")
+
+	require.NoError(t, os.WriteFile(pathManifest, []byte(manifest), 0o600))
+
+	return pathManifest
+}
+
+// It writes the files to a temporary directory and returns its path. The keys
+// of files are the slash separated paths.
+func writeTree(t *testing.T, files map[string]string) string {
+	t.Helper()
+
+	dirRoot := t.TempDir()
+
+	for path, content := range files {
+		pathFile := filepath.Join(dirRoot, filepath.FromSlash(path))
+
+		require.NoError(t, os.MkdirAll(filepath.Dir(pathFile), 0o700))
+		require.NoError(t, os.WriteFile(pathFile, []byte(content), 0o600))
+	}
+
+	return dirRoot
+}
+
 func mockSTDIN(t *testing.T, input string) func() {
 	t.Helper()
 
@@ -345,6 +2643,21 @@ func mockSTDIN(t *testing.T, input string) func() {
 	}
 }
 
+// It sets the environment variables and returns the function to unset them.
+func setEnvs(t *testing.T, envs map[string]string) func() {
+	t.Helper()
+
+	for name, value := range envs {
+		require.NoError(t, os.Setenv(name, value))
+	}
+
+	return func() {
+		for name := range envs {
+			os.Unsetenv(name)
+		}
+	}
+}
+
 func setDummyArgs(t *testing.T, args []string) func() {
 	t.Helper()
 
@@ -0,0 +1,167 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/pflag"
+)
+
+// ----------------------------------------------------------------------------
+//  Private Functions
+// ----------------------------------------------------------------------------
+
+// It runs the man subcommand. It prints the man page in roff format generated
+// from the flag definitions. Such as:
+//
+//	genrawid man | gzip -c > genrawid.1.gz
+func runMan() error {
+	setFlags()
+
+	writeMan(os.Stdout, pflag.CommandLine, []flagsCommand{
+		{name: cmdConvert, flags: newFlagsConvert(new(string), new(string))},
+		{name: cmdWatch, flags: newFlagsWatch(new(time.Duration), new(string)), isFiles: true},
+		{name: cmdDupes, flags: newFlagsDupes(new(bool), new(bool)), isFiles: true},
+		{name: cmdAudit, flags: newFlagsAudit(new(bool)), isFiles: true},
+	})
+
+	return nil
+}
+
+// It writes the man page. The subcommands are with their own flags.
+func writeMan(out io.Writer, flags *pflag.FlagSet, subcommands []flagsCommand) {
+	fmt.Fprintf(out, `.TH GENRAWID 1 "" "genrawid" "User Commands"
+.SH NAME
+genrawid \- generates a unique consistent number from the input as a rawid of SQLite3
+.SH SYNOPSIS
+.B genrawid
+[\fIflags\fR] [\fIfilepath\fR | \fB\-\fR]...
+.br
+.B genrawid convert
+\fB\-\-to\fR \fIrepresentation\fR [\fIrawid\fR]...
+.br
+.B genrawid config show
+[\fIflags\fR]
+.br
+.B genrawid watch
+[\fIflags\fR] \fIdir\fR...
+.br
+.B genrawid dupes
+[\fIflags\fR] \fIpath\fR...
+.br
+.B genrawid audit
+[\fIflags\fR] \fIpath\fR...
+.br
+.B genrawid audit \-\-from\-manifest
+[\fIflags\fR] [\fImanifest\fR]...
+.br
+.B genrawid completion
+\fBbash\fR|\fBzsh\fR|\fBfish\fR
+.br
+.B genrawid man
+.SH DESCRIPTION
+%s
+.SH OPTIONS
+`, escapeRoff(strings.Join([]string{
+		"It prints the rawid of the input, the 8 Bytes unique and consistent number",
+		"for the rowid of SQLite3. The default is the signed decimal of the",
+		"blake3-512 hash and the crc32c checksum of the hash.",
+		"If \"filepath\" is \"-\" then it reads from the piped STDIN.",
+		"If more than one is given, it prints a line of \"<rawid>  <filepath>\" for each.",
+	}, "\n")))
+
+	writeManFlags(out, flags)
+
+	fmt.Fprint(out, ".SH COMMANDS\n")
+
+	for _, command := range commands {
+		fmt.Fprintf(out, ".TP\n.B %s\n%s\n", command.name, escapeRoff(command.desc))
+
+		for _, subcommand := range subcommands {
+			if subcommand.name == command.name {
+				fmt.Fprint(out, ".RS\n")
+				writeManFlags(out, subcommand.flags)
+				fmt.Fprint(out, ".RE\n")
+			}
+		}
+	}
+
+	fmt.Fprint(out, ".SH ENVIRONMENT\n")
+
+	for _, key := range keysConfig {
+		fmt.Fprintf(out, ".TP\n.B %s\n%s\n", escapeRoff(nameEnv(key)),
+			escapeRoff(fmt.Sprintf("The default of --%s option.", key)))
+	}
+
+	fmt.Fprintf(out, ".TP\n.B %s\n%s\n", escapeRoff(nameEnv("config")), escapeRoff(strings.Join([]string{
+		"The path of the config file of \"<key> = <value>\" lines, such as \"hash = sha3-512\".",
+		"The default is \"genrawid/config\" in the user config directory.",
+		"The precedence is: flags > environment variables > config file > built-in defaults.",
+		"The precedence applies to --base62 and --hex, and to --fast, --hash, --hash-len, --checksum and --crc-poly as groups.",
+		"Only the ones of a group set in the highest of them are taken, and the rest of the group in the lower ones is ignored.",
+	}, "\n")))
+
+	fmt.Fprint(out, ".SH \"EXIT STATUS\"\n")
+
+	for _, status := range []struct {
+		code int
+		desc string
+	}{
+		{0, "Success."},
+		{ExitMismatch, "The rawids did not match on --verify or --check, or collided on audit."},
+		{ExitFailure, "Operational errors, such as unreadable inputs and invalid flags."},
+		{ExitUnknownAlgo, "Unknown hash or checksum algorithm."},
+		{ExitInvalidLength, "Invalid length of the hash."},
+		{ExitInvalidRawid, "Invalid rawid given, such as of --verify, convert or the manifest."},
+	} {
+		fmt.Fprintf(out, ".TP\n.B %d\n%s\n", status.code, escapeRoff(status.desc))
+	}
+
+	fmt.Fprint(out, `.SH "SEE ALSO"
+.BR sqlite3 (1)
+.PP
+https://github.com/KEINOS/go\-genrawid
+`)
+}
+
+// It writes the flags as the tagged paragraphs, such as:
+//
+//	.TP
+//	\fB\-c\fR, \fB\-\-check\fR
+//	description
+func writeManFlags(out io.Writer, flags *pflag.FlagSet) {
+	flags.VisitAll(func(flag *pflag.Flag) {
+		names := `\fB\-\-` + escapeRoff(flag.Name) + `\fR`
+		if flag.Shorthand != "" {
+			names = `\fB\-` + escapeRoff(flag.Shorthand) + `\fR, ` + names
+		}
+
+		if !isFlagBool(flag) {
+			names += ` \fI` + escapeRoff(flag.Value.Type()) + `\fR`
+		}
+
+		desc := flag.Usage
+		if !isFlagBool(flag) && flag.DefValue != "" && flag.DefValue != "[]" && flag.DefValue != "0" {
+			desc += fmt.Sprintf(" (default %q)", flag.DefValue)
+		}
+
+		fmt.Fprintf(out, ".TP\n%s\n%s\n", names, escapeRoff(desc))
+	})
+}
+
+// It escapes the text for roff. The lines beginning with "." or "'" are taken
+// as the requests otherwise.
+func escapeRoff(text string) string {
+	lines := strings.Split(strings.NewReplacer(`\`, `\e`, `-`, `\-`).Replace(text), "\n")
+
+	for i, line := range lines {
+		if strings.HasPrefix(line, ".") || strings.HasPrefix(line, "'") {
+			lines[i] = `\&` + line
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,242 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Intervals to report the progress of --progress option. The terminal is
+// redrawn more often than the lines for the machines are emitted.
+const (
+	intervalProgress    = time.Second
+	intervalProgressTTY = 200 * time.Millisecond
+)
+
+// IsTerminal returns true if the file is a terminal. It is a variable to ease
+// testing. Mock this variable during tests.
+var IsTerminal = func(file *os.File) bool {
+	info, err := file.Stat()
+
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// ----------------------------------------------------------------------------
+//  Private Functions
+// ----------------------------------------------------------------------------
+
+// It returns the input that reports the progress of reading it to stderr on
+// --progress option, and the function to stop reporting. The stop function must
+// be called after reading. It returns the input as is without the option.
+//
+// The size of the input is known if it is a regular file, such as stdin
+// redirected from a file. Otherwise the percentage and ETA are not reported.
+func withProgress(name string, input io.Reader) (io.Reader, func()) {
+	if !isProgress {
+		return input, func() {}
+	}
+
+	total := int64(-1)
+
+	if file, ok := input.(*os.File); ok {
+		if info, err := file.Stat(); err == nil && info.Mode().IsRegular() {
+			total = info.Size()
+		}
+	}
+
+	prog := startProgress(os.Stderr, name, total)
+
+	return &progressReader{input: input, prog: prog}, prog.stop
+}
+
+// ----------------------------------------------------------------------------
+//  Type: progress
+// ----------------------------------------------------------------------------
+
+// progress reports the bytes read of an input periodically until stop is called.
+// On a terminal it redraws a line of the bytes, the percentage, the throughput
+// and the ETA. Otherwise it emits a JSON object per line. See progressStat.
+type progress struct {
+	// read is accessed atomically. It must be the first field to be 64-bit
+	// aligned on the 32-bit platforms, such as ARM32.
+	read  int64
+	out   io.Writer
+	done  chan struct{}
+	start time.Time
+	name  string
+	wg    sync.WaitGroup
+	total int64 // -1 if unknown
+	isTTY bool
+}
+
+// progressStat is the progress of an input at a time. It is the line emitted
+// when stderr is not a terminal. Such as:
+//
+//	{"path":"disk.img","bytes":1048576,"total":4194304,"percent":25,"bytes_per_sec":524288,"eta_sec":6,"done":false}
+//
+// The total, percent and eta_sec are omitted if the size of the input is unknown.
+type progressStat struct {
+	Path        string   `json:"path"`
+	Bytes       int64    `json:"bytes"`
+	Total       *int64   `json:"total,omitempty"`
+	Percent     *float64 `json:"percent,omitempty"`
+	BytesPerSec float64  `json:"bytes_per_sec"`
+	ETASec      *float64 `json:"eta_sec,omitempty"`
+	Done        bool     `json:"done"`
+}
+
+func startProgress(out io.Writer, name string, total int64) *progress {
+	prog := &progress{
+		out:   out,
+		done:  make(chan struct{}),
+		start: time.Now(),
+		name:  name,
+		total: total,
+	}
+
+	interval := intervalProgress
+
+	if file, ok := out.(*os.File); ok && IsTerminal(file) {
+		prog.isTTY = true
+		interval = intervalProgressTTY
+	}
+
+	prog.wg.Add(1)
+
+	go func() {
+		defer prog.wg.Done()
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-prog.done:
+				return
+			case <-ticker.C:
+				prog.report(false)
+			}
+		}
+	}()
+
+	return prog
+}
+
+// It stops reporting and reports the final progress.
+func (p *progress) stop() {
+	close(p.done)
+	p.wg.Wait()
+
+	p.report(true)
+}
+
+func (p *progress) report(isDone bool) {
+	stat := p.stat(isDone)
+
+	if !p.isTTY {
+		line, _ := json.Marshal(stat)
+		fmt.Fprintf(p.out, "%s\n", line)
+
+		return
+	}
+
+	// Redraw the line. The final one is left with a line break
+	fmt.Fprintf(p.out, "\r\x1b[K%s", formatProgress(stat))
+
+	if isDone {
+		fmt.Fprintln(p.out)
+	}
+}
+
+func (p *progress) stat(isDone bool) progressStat {
+	read := atomic.LoadInt64(&p.read)
+
+	stat := progressStat{
+		Path:  p.name,
+		Bytes: read,
+		Done:  isDone,
+	}
+
+	if elapsed := time.Since(p.start).Seconds(); elapsed > 0 {
+		stat.BytesPerSec = float64(read) / elapsed
+	}
+
+	if p.total < 0 {
+		return stat
+	}
+
+	total := p.total
+	percent := float64(100)
+
+	if total > 0 {
+		percent = float64(read) * 100 / float64(total)
+	}
+
+	stat.Total, stat.Percent = &total, &percent
+
+	if stat.BytesPerSec > 0 {
+		eta := float64(total-read) / stat.BytesPerSec
+		stat.ETASec = &eta
+	}
+
+	return stat
+}
+
+// It returns the line of the progress for the terminal. Such as:
+//
+//	disk.img: 1.2 GiB / 40.0 GiB (3.0%)  350.2 MiB/s  ETA 1m52s
+func formatProgress(stat progressStat) string {
+	line := stat.Path + ": " + formatBytes(float64(stat.Bytes))
+
+	if stat.Total != nil {
+		line += fmt.Sprintf(" / %s (%.1f%%)", formatBytes(float64(*stat.Total)), *stat.Percent)
+	}
+
+	line += "  " + formatBytes(stat.BytesPerSec) + "/s"
+
+	if stat.ETASec != nil && !stat.Done {
+		line += "  ETA " + (time.Duration(*stat.ETASec) * time.Second).String()
+	}
+
+	return line
+}
+
+// It returns the bytes in the binary units, such as "1.5 KiB".
+func formatBytes(size float64) string {
+	const unit = 1024
+
+	if size < unit {
+		return fmt.Sprintf("%.0f B", size)
+	}
+
+	prefixes := "KMGTPE"
+	exp := 0
+
+	for size /= unit; size >= unit && exp < len(prefixes)-1; exp++ {
+		size /= unit
+	}
+
+	return fmt.Sprintf("%.1f %ciB", size, prefixes[exp])
+}
+
+// ----------------------------------------------------------------------------
+//  Type: progressReader
+// ----------------------------------------------------------------------------
+
+// progressReader is an io.Reader that counts the bytes read for progress.
+type progressReader struct {
+	input io.Reader
+	prog  *progress
+}
+
+// Read implements io.Reader.
+func (r *progressReader) Read(p []byte) (int, error) {
+	n, err := r.input.Read(p)
+	atomic.AddInt64(&r.prog.read, int64(n))
+
+	return n, err
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/KEINOS/go-genrawid"
+	"github.com/KEINOS/go-genrawid/pkg/hasher"
+	"github.com/pkg/errors"
+)
+
+// ----------------------------------------------------------------------------
+//  Private Functions
+// ----------------------------------------------------------------------------
+
+// It validates the options of --lines and --null. The input is stdin if none
+// given.
+func chkOptRecords(args []string) error {
+	if !isLines && !isNull {
+		if isStripCR || isWithRecord {
+			return errors.New("--strip-cr and --with-record options are meaningful only with --lines or --null option")
+		}
+
+		return nil
+	}
+
+	switch {
+	case isLines && isNull:
+		return errors.New("--lines and --null options can not be used together")
+	case isStripCR && !isLines:
+		return errors.New("--strip-cr option is meaningful only with --lines option")
+	case isCheck:
+		return errors.New("--lines and --null options can not be used with --check option")
+	case inVerify != "":
+		return errors.New("--lines and --null options can not be used with --verify option")
+	case len(inSchemes) > 0:
+		return errors.New("--lines and --null options can not be used with --scheme option")
+	case inFormat != "":
+		return errors.New("--lines and --null options can not be used with --format option")
+	case len(args) > 1 || isRecursive:
+		return errors.New("--lines and --null options can not be used with more than one input")
+	}
+
+	if len(args) == 0 && inStr == "" {
+		isStdin = true
+	}
+
+	return nil
+}
+
+// It computes the rawid of each record of the input and prints a rawid per
+// record in the same order. The records are split by "\n" on --lines option and
+// by NUL on --null option. With --with-record option, the record follows the
+// rawid as "<rawid>  <record>".
+//
+// With --strip-cr option, the "\r" at the end of the lines is removed from the
+// records. It changes the rawids only in fast mode, since BLAKE3 and SHA3-512
+// ignore the line breaks including it. Though, it is removed from the records
+// printed with --with-record option in any mode.
+//
+// The outputs of --null option are NUL-terminated as well, since the records
+// may contain line breaks.
+func runRecords() error {
+	input, closeInput, err := openInput()
+	if err != nil {
+		return err
+	}
+
+	defer closeInput()
+
+	input, stopProgress := withProgress(nameInput(), input)
+	defer stopProgress()
+
+	output := bufio.NewWriter(os.Stdout)
+
+	// Flush the outputs of the records before the error as well
+	err = computeRecords(input, output)
+	if errFlush := output.Flush(); err == nil && errFlush != nil {
+		err = errors.Wrap(errFlush, "failed to write the output")
+	}
+
+	return err
+}
+
+// It reads the records of the input and writes the rawid of each to output.
+func computeRecords(input io.Reader, output io.Writer) error {
+	delim, term := byte('\n'), "\n"
+	if isNull {
+		delim, term = 0, "\x00"
+	}
+
+	reader := bufio.NewReader(input)
+
+	for {
+		record, errRead := reader.ReadBytes(delim)
+
+		// The last record may not be terminated. Nothing remains if it was.
+		if len(record) > 0 {
+			record = bytes.TrimSuffix(record, []byte{delim})
+			if isStripCR {
+				record = bytes.TrimSuffix(record, []byte{'\r'})
+			}
+
+			//nolint:varnamelen // allow short variable names for readability
+			id, err := genrawid.FromString(string(record))
+			if err != nil {
+				return errors.Wrap(err, "failed to generate rawid")
+			}
+
+			if isWithRecord {
+				fmt.Fprintf(output, "%s  %s%s", formatRawid(id), record, term)
+			} else {
+				fmt.Fprintf(output, "%s%s", formatRawid(id), term)
+			}
+		}
+
+		if errors.Is(errRead, io.EOF) {
+			return nil
+		}
+
+		if errRead != nil {
+			return errors.Wrap(hasher.NewErrRead(errRead), "failed to read the records")
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/KEINOS/go-genrawid"
+	"github.com/KEINOS/go-genrawid/pkg/hasher"
+	"github.com/pkg/errors"
+)
+
+// ----------------------------------------------------------------------------
+//  Private Functions
+// ----------------------------------------------------------------------------
+
+// It computes the rawids of the schemes given via --scheme options in a single
+// pass and prints them.
+//
+// If only one scheme is given, it prints the rawid as usual. Otherwise it prints
+// a line of "<scheme> <rawid>" for each scheme in the given order.
+func runSchemes() error {
+	// The schemes determine them. E.g. "v1/blake3:<context>/512/crc32c" for --context
+	if isFast || isAlgoChanged(hasher.HashAlgo, hasher.CRC32Poly) || inContext != "" {
+		return errors.New("--scheme option can not be used with --fast, --context or the algorithm options")
+	}
+
+	if isVerify && len(inSchemes) > 1 {
+		return errors.New("--verify option can not be used with more than one --scheme option")
+	}
+
+	gens := make([]*genrawid.Generator, len(inSchemes))
+
+	for i, name := range inSchemes {
+		gen, err := genrawid.ParseScheme(name)
+		if err != nil {
+			return errors.Wrap(err, "invalid --scheme option")
+		}
+
+		gens[i] = gen
+	}
+
+	input, closeInput, err := openInput()
+	if err != nil {
+		return err
+	}
+
+	defer closeInput()
+
+	input, stopProgress := withProgress(nameInput(), input)
+	defer stopProgress()
+
+	results, err := genrawid.ComputeMulti(input, gens...)
+	if err != nil {
+		return errors.Wrap(err, "failed to generate rawids")
+	}
+
+	if inFormat != "" {
+		records := make([]record, len(results))
+
+		for i, result := range results {
+			records[i] = record{path: nameInput(), result: result}
+		}
+
+		return printRecords(records...)
+	}
+
+	if len(results) == 1 {
+		return printRawid(results[0].ID)
+	}
+
+	for _, result := range results {
+		//nolint:forbidigo // allow printing to stdout
+		fmt.Printf("%s %s\n", result.Scheme, formatRawid(result.ID))
+	}
+
+	return nil
+}
+
+// It returns the name of the input for the path field of --format option. It is
+// empty for --string option and "-" for stdin.
+func nameInput() string {
+	switch {
+	case isString:
+		return ""
+	case isFile:
+		return pathFile
+	}
+
+	return "-"
+}
+
+// It returns the input to read of the --string option, the file path or stdin.
+func openInput() (io.Reader, func(), error) {
+	switch {
+	case isString:
+		return strings.NewReader(inStr), func() {}, nil
+	case isFile:
+		file, err := os.Open(pathFile)
+		if err != nil {
+			return nil, nil, errors.Wrap(hasher.NewErrRead(err), "failed to read from file")
+		}
+
+		return file, func() { file.Close() }, nil
+	}
+
+	return genrawid.OsStdin, func() {}, nil
+}
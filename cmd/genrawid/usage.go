@@ -13,9 +13,51 @@ func usage() {
 		genrawid - generates a unique consistent number from the imput.
 
 		Usage:
-		  genrawid [flags] [filepath | - ]
+		  genrawid [flags] [filepath | - ]...
+		  genrawid convert --to <dec|udec|hex|base62|int64> [rawid]...
+		  genrawid config show [flags]
+		  genrawid watch [flags] <dir>...
+		  genrawid dupes [flags] <path>...
+		  genrawid audit [flags] <path>...
+		  genrawid completion <bash|zsh|fish>
+		  genrawid man
 
 		  If "filepath" argument is "-" then it will read from the piped STDIN.
+		  If more than one is given, it prints a line of "<rawid>  <filepath>"
+		  for each.
+
+		Commands:
+		  convert     converts the representations of rawids, such as from the
+		              decimal to Base62. See "genrawid convert --help".
+		  config      "config show" prints the effective settings and their
+		              sources.
+		  watch       keeps the rawids of the directories up to date. It prints
+		              the changes as NDJSON or rewrites a manifest. See
+		              "genrawid watch --help".
+		  dupes       finds the duplicate files and optionally replaces them
+		              with the hard links. See "genrawid dupes --help".
+		  audit       reports the rawids shared by different contents and the
+		              observed versus expected collisions of a corpus. See
+		              "genrawid audit --help".
+		  completion  prints the completion script of the shell (bash, zsh,
+		              fish). Such as: source <(genrawid completion bash)
+		  man         prints the man page in roff format.
+
+		Configuration:
+		  The defaults of --base62, --checksum, --concurrency, --context,
+		  --crc-poly, --fast, --hash, --hash-len, --hex, --jobs and --new-line
+		  can be set via the environment variables, such as GENRAWID_HASH and
+		  GENRAWID_CRC_POLY, and the config file of "<key> = <value>" lines,
+		  such as "hash = sha3-512". The config file is the one of --config,
+		  GENRAWID_CONFIG or "genrawid/config" in the user config directory,
+		  such as $XDG_CONFIG_HOME. The precedence is:
+
+		    flags > environment variables > config file > built-in defaults
+
+		  The precedence applies to --base62 and --hex, and to --fast, --hash,
+		  --hash-len, --checksum and --crc-poly as groups. Only the ones of a
+		  group set in the highest of them are taken, and the rest of the group
+		  in the lower ones is ignored.
 	`))
 
 	fmt.Fprintln(os.Stderr, "Flags:")
@@ -27,6 +69,34 @@ func usage() {
 		  $ # To specify a file to generate its rawid.
 		  $ genrawid /path/to/my/file.pdf
 
+		  $ # Multiple files. The files that can not be read are reported to
+		  $ # STDERR and it exits with non-zero status at the end.
+		  $ genrawid file1.txt file2.txt dir/*.pdf
+
+		  $ # Read the files in the directory recursively in the lexical order.
+		  $ # Skip the files and directories matching the patterns. The patterns
+		  $ # with "/" match the relative path and the others the base name.
+		  $ genrawid -r --exclude "*.tmp" --exclude ".git/" /path/to/my/dir
+
+		  $ # Compute the files with 4 workers. The results are printed in the
+		  $ # order of the inputs. The default is the number of CPUs.
+		  $ genrawid -j 4 -r /path/to/my/dir
+
+		  $ # Verify the files listed in the manifest, such as the output of the
+		  $ # above. The rawids can be any of decimal, hex or Base62. Use --quiet
+		  $ # to print only the failures and --status to print nothing.
+		  $ genrawid file1.txt file2.txt > manifest.txt
+		  $ genrawid --check manifest.txt
+
+		  $ # Print the results as JSON, NDJSON, CSV or TSV with the fields to
+		  $ # output, such as path, size, dec, udec, hex, base62, scheme and error.
+		  $ genrawid --format csv --fields path,size,hex file1.txt file2.txt
+
+		  $ # Print the rawid of each line of the input, such as a list of URLs,
+		  $ # followed by the line. Use -z (--null) for NUL-terminated records,
+		  $ # such as the output of "find -print0".
+		  $ cat urls.txt | genrawid --lines --strip-cr --with-record
+
 		  $ # Specify a file content via STDIN. The following two are equivalent.
 		  $ genrawid - < /path/to/my/file.pdf
 		  $ cat /path/to/my/file.pdf | genrawid -
@@ -40,10 +110,50 @@ func usage() {
 		  $ # Adds a line break to the output.
 		  $ genrawid -s "foo bar" --new-line
 
+		  $ # Use XOR16 as a checksum instead of CRC-32C. The rawid will contain
+		  $ # 6 Bytes of the hash and 2 Bytes of the checksum.
+		  $ genrawid -s "foo bar" --checksum xor16
+
+		  $ # Use SHA3-512 and CRC-32 with IEEE polynomial, such as to reproduce
+		  $ # the rawids of the library with hasher.HashAlgo and hasher.CRC32Poly.
+		  $ # See --list-algorithms for the available ones.
+		  $ genrawid --hash sha3-512 --crc-poly ieee /path/to/my/file.pdf
+
+		  $ # Fast mode. It hashes the input only once with XXH64. Note that the
+		  $ # rawids in fast mode differ from the regular ones and not comparable.
+		  $ genrawid --fast /path/to/my/file.pdf
+
+		  $ # Compute the rawids of more than one scheme in a single pass. Such
+		  $ # as migrating from SHA3-512 based rawids to the default ones.
+		  $ genrawid --scheme v1/sha3-512/512/crc32c --scheme default /path/to/my/file.pdf
+
+		  $ # Hash a large file with BLAKE3 using all the CPUs. The rawid is the
+		  $ # same as the one computed with a single thread, which is used with
+		  $ # less than 6 CPUs since it is faster than them.
+		  $ genrawid --concurrency 0 /path/to/my/large/file.iso
+
+		  $ # Report the progress to STDERR while hashing a large input. If
+		  $ # STDERR is not a terminal, it emits a JSON line per second.
+		  $ genrawid --progress /path/to/my/disk.img
+
+		  $ # Derive the rawid with a context string. The rawids of the same input
+		  $ # differ for each context. Such as to separate the rawids of the
+		  $ # manifests from the ones of the other files.
+		  $ genrawid --context "acme 2026 manifest v1" /path/to/my/manifest.json
+
 		  $ # Verify if rawid is equivalent to the given rawid. It will exit with
-		  $ # status 0 if matches, and 1 if not.
+		  $ # status 0 if matches, and 1 if not. The given rawid can be any of the
+		  $ # signed or unsigned decimal, hex with or without "0x", or Base62.
 		  $ genrawid -s "foo bar" --verify "-7374369981397550869"
 
+		Exit status:
+		  0  success
+		  1  the rawids did not match on --verify or --check, or collided on audit
+		  2  operational errors, such as unreadable inputs and invalid flags
+		  3  unknown hash or checksum algorithm
+		  4  invalid length of the hash
+		  5  invalid rawid given, such as of --verify, convert or the manifest
+
 		About:
 		  genrawid is a niche tool to generate a unique number from the input.
 
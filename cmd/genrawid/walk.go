@@ -0,0 +1,239 @@
+package main
+
+import (
+	"bufio"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/KEINOS/go-genrawid/pkg/hasher"
+	"github.com/pkg/errors"
+	"github.com/spf13/pflag"
+)
+
+// flagsWalkRejected is the names of the main flags that the subcommands walking
+// the directories, such as watch, do not take. They are of the inputs and the
+// outputs of the main command, and the ones meaningful only with them. See
+// chkOptWalkCommand.
+var flagsWalkRejected = []string{
+	"check", "quiet", "status",
+	"lines", "null", "strip-cr", "with-record",
+	"format", "fields",
+	"string", "verify", "scheme", "list-algorithms",
+}
+
+// ----------------------------------------------------------------------------
+//  Private Functions
+// ----------------------------------------------------------------------------
+
+// It validates the main flags given to the subcommand of the name that walks
+// the directories, such as watch, and sets the options of the rawids. The flags
+// of flagsWalkRejected are rejected, and the directories are walked the same as
+// --recursive option with --exclude, --include and the others.
+func chkOptWalkCommand(name string) error {
+	for _, nameFlag := range flagsWalkRejected {
+		if pflag.Lookup(nameFlag).Changed {
+			return errors.Errorf("--%s option can not be used with %s", nameFlag, name)
+		}
+	}
+
+	isRecursive = true
+
+	for _, chkOpt := range []func() error{chkOptRecursive, chkOptChkSum, chkOptAlgo} {
+		if err := chkOpt(); err != nil {
+			return err
+		}
+	}
+
+	chkOptConcurrency()
+	chkOptContext()
+	chkModeFast()
+
+	return nil
+}
+
+// It validates the options of --recursive and reads the patterns of the ignore
+// file to inExcludes.
+func chkOptRecursive() error {
+	if !isRecursive {
+		if len(inExcludes) > 0 || len(inIncludes) > 0 || inIgnoreFile != "" || isFollowSymlinks {
+			return errors.New(
+				"--exclude, --include, --ignore-file and --follow-symlinks options are meaningful only with --recursive option",
+			)
+		}
+
+		return nil
+	}
+
+	if inIgnoreFile != "" {
+		patterns, err := readIgnoreFile(inIgnoreFile)
+		if err != nil {
+			return err
+		}
+
+		inExcludes = append(inExcludes, patterns...)
+	}
+
+	for _, patterns := range [][]string{inExcludes, inIncludes} {
+		for _, pattern := range patterns {
+			if _, err := path.Match(filepath.ToSlash(pattern), ""); err != nil {
+				return errors.Wrapf(err, "invalid pattern: %s", pattern)
+			}
+		}
+	}
+
+	return nil
+}
+
+// It returns the patterns in the ignore file. The lines are glob patterns the
+// same as --exclude option. Empty lines and the lines beginning with "#" are
+// skipped.
+func readIgnoreFile(pathIgnore string) ([]string, error) {
+	file, err := os.Open(pathIgnore)
+	if err != nil {
+		return nil, errors.Wrap(hasher.NewErrRead(err), "failed to open ignore file")
+	}
+
+	defer file.Close()
+
+	var patterns []string
+
+	scanner := bufio.NewScanner(file)
+
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+
+		if line != "" && !strings.HasPrefix(line, "#") {
+			patterns = append(patterns, line)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, errors.Wrap(hasher.NewErrRead(err), "failed to read ignore file")
+	}
+
+	return patterns, nil
+}
+
+// It returns true if path is an existing directory.
+func isDir(path string) bool {
+	info, err := os.Stat(path)
+
+	return err == nil && info.IsDir()
+}
+
+// It walks the directory and calls visit for each regular file in it. The order
+// is the lexical order of the names in each directory, so that the outputs of
+// two runs can be diffed.
+//
+// The files and the directories that match --exclude patterns are skipped. If
+// --include patterns are given, only the files that match them are visited.
+// The symbolic links are skipped unless --follow-symlinks option is set.
+//
+// The errors while walking are passed to visit with the path as well.
+func walkDir(root string, visit func(path string, err error)) {
+	walkDirRecursive(root, root, nil, visit)
+}
+
+func walkDirRecursive(root, dir string, ancestors []fs.FileInfo, visit func(path string, err error)) {
+	info, err := os.Stat(dir)
+	if err != nil {
+		visit(dir, hasher.NewErrRead(err))
+
+		return
+	}
+
+	// Following the symbolic links may loop
+	for _, ancestor := range ancestors {
+		if os.SameFile(ancestor, info) {
+			visit(dir, errors.New("symbolic link loop detected"))
+
+			return
+		}
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		visit(dir, hasher.NewErrRead(err))
+
+		return
+	}
+
+	ancestors = append(ancestors, info)
+
+	for _, entry := range entries {
+		pathEntry := filepath.Join(dir, entry.Name())
+
+		mode, err := entryMode(pathEntry, entry)
+		if err != nil {
+			visit(pathEntry, hasher.NewErrRead(err))
+
+			continue
+		}
+
+		// The relative path from the root to match the patterns
+		rel, _ := filepath.Rel(root, pathEntry)
+
+		switch {
+		case mode.IsDir():
+			if !matchAny(inExcludes, rel, true) {
+				walkDirRecursive(root, pathEntry, ancestors, visit)
+			}
+		case mode.IsRegular():
+			if !matchAny(inExcludes, rel, false) && (len(inIncludes) == 0 || matchAny(inIncludes, rel, false)) {
+				visit(pathEntry, nil)
+			}
+		}
+	}
+}
+
+// It returns the file mode of the entry. If the entry is a symbolic link, it
+// is the mode of the link target with --follow-symlinks option. Otherwise it
+// is the mode of the link itself, which is neither a directory nor a regular
+// file.
+func entryMode(pathEntry string, entry fs.DirEntry) (fs.FileMode, error) {
+	if entry.Type()&fs.ModeSymlink == 0 || !isFollowSymlinks {
+		return entry.Type(), nil
+	}
+
+	info, err := os.Stat(pathEntry)
+	if err != nil {
+		return 0, err
+	}
+
+	return info.Mode(), nil
+}
+
+// It returns true if the relative path matches any of the glob patterns.
+//
+// Like .gitignore, the patterns with "/" are matched to the relative path and
+// the others to the base name. The patterns ending with "/" match directories
+// only. Such as "*.tmp", "docs/*.md" and "node_modules/".
+func matchAny(patterns []string, rel string, isDirEntry bool) bool {
+	rel = filepath.ToSlash(rel)
+
+	for _, pattern := range patterns {
+		pattern = filepath.ToSlash(pattern)
+
+		if strings.HasSuffix(pattern, "/") {
+			if !isDirEntry {
+				continue
+			}
+
+			pattern = strings.TrimSuffix(pattern, "/")
+		}
+
+		target := path.Base(rel)
+		if strings.Contains(pattern, "/") {
+			target = rel
+		}
+
+		if ok, _ := path.Match(pattern, target); ok {
+			return true
+		}
+	}
+
+	return false
+}
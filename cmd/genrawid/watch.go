@@ -0,0 +1,403 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/KEINOS/go-genrawid/pkg/hasher"
+	"github.com/KEINOS/go-genrawid/pkg/rawid"
+	"github.com/KEINOS/go-utiles/util"
+	"github.com/pkg/errors"
+	"github.com/spf13/pflag"
+)
+
+// cmdWatch is the name of the subcommand to keep the rawids of directories up
+// to date.
+const cmdWatch = "watch"
+
+// Names of the events of the watch subcommand.
+const (
+	eventCreated  = "created"
+	eventDeleted  = "deleted"
+	eventModified = "modified"
+)
+
+// NewWatchContext returns the context to stop the watch subcommand. It is done
+// on SIGINT or SIGTERM. It is a variable to ease testing. Mock this variable
+// during tests.
+var NewWatchContext = func() (context.Context, context.CancelFunc) {
+	return signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+}
+
+// ----------------------------------------------------------------------------
+//  Private Functions
+// ----------------------------------------------------------------------------
+
+// It runs the watch subcommand with the args after "watch". It polls the
+// directories given at the interval and detects the created, modified and
+// deleted files by their size and modification time. Only the changed ones are
+// recomputed.
+//
+// It prints the changes as NDJSON. See watchEvent. With --manifest option, it
+// rewrites the manifest atomically on changes instead. The flags of the rawids
+// and --recursive option are available as well. It runs until interrupted.
+func runWatch(args []string) error {
+	var (
+		inInterval time.Duration
+		inManifest string
+	)
+
+	setFlags()
+
+	pflag.CommandLine.AddFlagSet(newFlagsWatch(&inInterval, &inManifest))
+
+	if err := pflag.CommandLine.Parse(args); err != nil {
+		return errors.Wrap(err, "invalid flags")
+	}
+
+	if err := loadConfig(); err != nil {
+		return err
+	}
+
+	if isHelp {
+		usageWatch()
+
+		return nil
+	}
+
+	roots, err := chkOptWatch(pflag.Args(), inInterval)
+	if err != nil {
+		return err
+	}
+
+	watch := newWatcher(roots, inManifest)
+
+	ctx, cancel := NewWatchContext()
+	defer cancel()
+
+	for isFirst := true; ; isFirst = false {
+		if err := watch.report(watch.scan(), isFirst); err != nil {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(inInterval):
+		}
+	}
+}
+
+// It returns the flags of the watch subcommand besides the main ones. It is used
+// for the completions and the man page as well.
+func newFlagsWatch(inInterval *time.Duration, inManifest *string) *pflag.FlagSet {
+	const intervalDefault = 2 * time.Second
+
+	flags := pflag.NewFlagSet(cmdWatch, pflag.ContinueOnError)
+
+	flags.DurationVar(inInterval, "interval", intervalDefault, "interval to poll the directories (e.g. 500ms, 10s, 1m)")
+	flags.StringVar(inManifest, "manifest", "", "manifest file to rewrite atomically on changes instead of printing the events")
+
+	return flags
+}
+
+// It validates the options of the watch subcommand and sets the ones of the
+// rawids. It returns the directories to watch.
+func chkOptWatch(args []string, interval time.Duration) ([]string, error) {
+	switch {
+	case len(args) == 0:
+		return nil, errors.New("missing directory. usage: genrawid watch [flags] <dir>...")
+	case interval <= 0:
+		return nil, errors.Errorf("invalid --interval option: %v. it must be positive", interval)
+	}
+
+	for _, root := range args {
+		if !isDir(root) {
+			return nil, errors.Wrapf(hasher.NewErrRead(os.ErrNotExist), "not a directory: %s", root)
+		}
+	}
+
+	if err := chkOptWalkCommand(cmdWatch); err != nil {
+		return nil, err
+	}
+
+	return args, nil
+}
+
+func usageWatch() {
+	fmt.Fprintln(os.Stderr, util.HereDoc(`
+		genrawid watch - keeps the rawids of directories up to date.
+
+		Usage:
+		  genrawid watch [flags] <dir>...
+
+		  It polls the directories at the interval and prints a JSON line per
+		  created, modified and deleted file. The files are detected by their
+		  size and modification time, and only the changed ones are recomputed.
+		  The files existing at the start are reported as created.
+
+		  With --manifest, it rewrites the manifest of "<rawid>  <path>" lines
+		  atomically on changes instead, the same as "genrawid -r <dir>". It
+		  runs until interrupted.
+	`))
+
+	fmt.Fprintln(os.Stderr, "Flags:")
+	pflag.PrintDefaults()
+
+	fmt.Fprintln(os.Stderr, util.HereDoc(`
+
+		Example:
+		  $ genrawid watch --interval 10s ./incoming
+		  {"event":"created","path":"incoming/a.txt","size":8,"rawid":"-2474118025671277174"}
+
+		  $ # Keep the manifest up to date for "genrawid --check"
+		  $ genrawid watch --manifest manifest.txt --exclude "*.part" ./incoming
+	`))
+}
+
+// ----------------------------------------------------------------------------
+//  Type: watcher
+// ----------------------------------------------------------------------------
+
+// watcher holds the files of the last scan of the directories.
+type watcher struct {
+	entries      map[string]watchEntry // by the path
+	pathManifest string                // rewrites the manifest instead of printing the events if set.
+	roots        []string
+}
+
+// watchEntry is the state of a file at the last scan.
+type watchEntry struct {
+	modTime time.Time
+	id      rawid.ID
+	size    int64
+}
+
+// watchEvent is a change of a file. It is printed as a JSON line. Such as:
+//
+//	{"event":"modified","path":"incoming/a.txt","size":8,"rawid":"-2474118025671277174","previous":"351486625072160940"}
+//
+// The rawids are in the format of the output options. The size and the rawid of
+// the deleted files are of the last scan.
+type watchEvent struct {
+	Event    string `json:"event"`
+	Path     string `json:"path"`
+	Size     int64  `json:"size"`
+	Rawid    string `json:"rawid"`
+	Previous string `json:"previous,omitempty"`
+}
+
+func newWatcher(roots []string, pathManifest string) *watcher {
+	return &watcher{
+		entries:      map[string]watchEntry{},
+		pathManifest: pathManifest,
+		roots:        roots,
+	}
+}
+
+// It scans the directories and returns the changes since the last scan in the
+// lexical order of the paths. The deleted files follow the others.
+//
+// The files that can not be read are reported to stderr and retried on the next
+// scan. The deletions are not detected on such scans, since the files in the
+// directories that can not be read are not seen either.
+func (w *watcher) scan() []watchEvent {
+	var (
+		events     []watchEvent
+		next       = make(map[string]watchEntry, len(w.entries))
+		isComplete = true
+	)
+
+	printErr := func(path string, err error) {
+		fmt.Fprintf(os.Stderr, "%s: %v\n", path, err)
+	}
+
+	// The outputs of the tasks run one at a time. The entries of the last scan
+	// are only read while scanning.
+	_ = runOrdered(func(submit func(task)) error {
+		for _, root := range w.roots {
+			walkDir(root, func(path string, err error) {
+				if err == nil && w.isManifest(path) {
+					return
+				}
+
+				var info os.FileInfo
+
+				if err == nil {
+					info, err = os.Stat(path)
+				}
+
+				if err != nil {
+					isComplete = false
+
+					submit(func() func() { return func() { printErr(path, err) } })
+
+					return
+				}
+
+				prev, isKnown := w.entries[path]
+
+				if isKnown && prev.size == info.Size() && prev.modTime.Equal(info.ModTime()) {
+					submit(func() func() { return func() { next[path] = prev } })
+
+					return
+				}
+
+				submit(func() func() {
+					result, err := computeFile(path)
+
+					return func() {
+						if err != nil {
+							printErr(path, err)
+
+							if isKnown {
+								next[path] = prev
+							}
+
+							return
+						}
+
+						// The size and the time before computing. If the file was
+						// changed while computing, it will be detected on the next scan.
+						next[path] = watchEntry{modTime: info.ModTime(), id: result.ID, size: info.Size()}
+
+						switch {
+						case !isKnown:
+							events = append(events, watchEvent{
+								Event: eventCreated, Path: path, Size: info.Size(), Rawid: formatRawid(result.ID),
+							})
+						case !bytes.Equal(prev.id, result.ID):
+							events = append(events, watchEvent{
+								Event: eventModified, Path: path, Size: info.Size(), Rawid: formatRawid(result.ID),
+								Previous: formatRawid(prev.id),
+							})
+						}
+					}
+				})
+			})
+		}
+
+		return nil
+	})
+
+	for _, path := range sortedKeys(w.entries) {
+		if _, ok := next[path]; ok {
+			continue
+		}
+
+		if !isComplete {
+			next[path] = w.entries[path]
+
+			continue
+		}
+
+		events = append(events, watchEvent{
+			Event: eventDeleted, Path: path, Size: w.entries[path].size, Rawid: formatRawid(w.entries[path].id),
+		})
+	}
+
+	w.entries = next
+
+	return events
+}
+
+// It prints the events as JSON lines, or rewrites the manifest if there are any
+// changes or on the first scan.
+func (w *watcher) report(events []watchEvent, isFirst bool) error {
+	if w.pathManifest != "" {
+		if len(events) == 0 && !isFirst {
+			return nil
+		}
+
+		return w.writeManifest()
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+
+	for _, event := range events {
+		if err := encoder.Encode(event); err != nil {
+			return errors.Wrap(err, "failed to print the event")
+		}
+	}
+
+	return nil
+}
+
+// It writes the manifest to a temporary file in the same directory and renames
+// it, so that the readers never see a partially written one.
+func (w *watcher) writeManifest() error {
+	const permManifest = 0o644
+
+	dir, base := filepath.Split(w.pathManifest)
+
+	file, err := os.CreateTemp(dir, "."+base+".tmp")
+	if err != nil {
+		return errors.Wrap(err, "failed to create the manifest")
+	}
+
+	// Removes nothing after renamed
+	defer os.Remove(file.Name())
+
+	output := bufio.NewWriter(file)
+
+	for _, path := range sortedKeys(w.entries) {
+		fmt.Fprintf(output, "%s  %s\n", formatRawid(w.entries[path].id), path)
+	}
+
+	err = output.Flush()
+	if errClose := file.Close(); err == nil {
+		err = errClose
+	}
+
+	if err == nil {
+		err = os.Chmod(file.Name(), permManifest)
+	}
+
+	if err == nil {
+		err = os.Rename(file.Name(), w.pathManifest)
+	}
+
+	return errors.Wrap(err, "failed to write the manifest")
+}
+
+// It returns true if the path is the manifest or its temporary file, which are
+// not watched if in the directories.
+func (w *watcher) isManifest(path string) bool {
+	if w.pathManifest == "" {
+		return false
+	}
+
+	pathAbs, err1 := filepath.Abs(path)
+	manifestAbs, err2 := filepath.Abs(w.pathManifest)
+
+	if err1 != nil || err2 != nil {
+		return false
+	}
+
+	dir, base := filepath.Split(manifestAbs)
+
+	return pathAbs == manifestAbs ||
+		(filepath.Dir(pathAbs) == filepath.Clean(dir) && strings.HasPrefix(filepath.Base(pathAbs), "."+base+".tmp"))
+}
+
+func sortedKeys(entries map[string]watchEntry) []string {
+	keys := make([]string, 0, len(entries))
+
+	for key := range entries {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
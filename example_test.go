@@ -4,8 +4,10 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"strings"
 
 	"github.com/KEINOS/go-genrawid"
+	"github.com/KEINOS/go-genrawid/pkg/hasher"
 )
 
 // ----------------------------------------------------------------------------
@@ -28,6 +30,67 @@ func ExampleFromString() {
 	// -2474118025671277174
 }
 
+func ExampleCompute() {
+	result, err := genrawid.Compute(strings.NewReader("abcdefgh"))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	fmt.Println("rawid:", result.ID.Dec())
+	fmt.Printf("digest: %x...\n", result.Digest[:8])
+	fmt.Printf("checksum: %x\n", result.CheckSum)
+	fmt.Println("size:", result.Size)
+	fmt.Println("scheme:", result.Scheme)
+
+	// Output:
+	// rawid: -2474118025671277174
+	// digest: ddaa2ac30a986559...
+	// checksum: 9b79058a
+	// size: 8
+	// scheme: v1/blake3/512/crc32c
+}
+
+func ExampleGenerator_NewWriter() {
+	gen := genrawid.New()
+
+	// Write the first part of the input and save the state.
+	writer, err := gen.NewWriter()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	fmt.Fprint(writer, "abcd")
+
+	state, err := writer.MarshalBinary()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Later, restore the state and write the rest of the input.
+	resumed, err := gen.NewWriter()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if err := resumed.UnmarshalBinary(state); err != nil {
+		log.Fatal(err)
+	}
+
+	fmt.Fprint(resumed, "efgh")
+
+	result, err := resumed.Result()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	fmt.Println(result.ID.Dec())
+	fmt.Println(result.Size)
+
+	// Output:
+	// -2474118025671277174
+	// 8
+}
+
 func ExampleFromFile() {
 	const pathFile = "./testdata/msg.txt" // msg.txt ==> "abcdefgh"
 
@@ -65,11 +128,52 @@ func ExampleFromFile_fast_mode() {
 	fmt.Println(rawid.Hex())
 	fmt.Println(rawid.Dec())
 
+	// Output:
+	// 3ad351775b4634b7
+	// 4238821247360054455
+}
+
+func ExampleNew() {
+	// Generator with XOR16 as a checksum. The rawid consists of the first 6 Bytes
+	// of the hash and 2 Bytes of the checksum. This is equivalent to the former
+	// fast mode, which is "v1/blake3/512/xor16" now.
+	gen := genrawid.New(genrawid.WithChkSumAlgo(hasher.ChkSumXOR16))
+
+	rawid, err := gen.FromString("abcdefgh")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	fmt.Println(rawid.Hex())
+	fmt.Println(rawid.Dec())
+
 	// Output:
 	// ddaa2ac30a98963b
 	// -2474118028101904837
 }
 
+func ExampleGenerator_Scheme() {
+	for _, gen := range []*genrawid.Generator{
+		genrawid.New(),
+		genrawid.New(genrawid.WithHashAlgo(hasher.HashAlgoSHA3_512), genrawid.WithHashLen(32)),
+		genrawid.New(genrawid.WithChkSumAlgo(hasher.ChkSumXOR16)),
+		genrawid.New(genrawid.WithFastMode(true)),
+	} {
+		rawid, err := gen.FromString("abcdefgh")
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		fmt.Println(rawid.Hex(), gen.Scheme())
+	}
+
+	// Output:
+	// ddaa2ac39b79058a v1/blake3/512/crc32c
+	// c9f25eeedb9444a8 v1/sha3-512/256/crc32c
+	// ddaa2ac30a98963b v1/blake3/512/xor16
+	// 3ad351775b4634b7 fast-v2/xxh64
+}
+
 // ExampleFromStdin
 //
 // Since we can not receive input from stdin during the example run, we mock
@@ -0,0 +1,213 @@
+package genrawid
+
+import (
+	"bytes"
+	"io"
+	"strings"
+
+	"github.com/KEINOS/go-genrawid/pkg/hasher"
+	"github.com/KEINOS/go-genrawid/pkg/rawid"
+	"github.com/pkg/errors"
+)
+
+// ----------------------------------------------------------------------------
+//  Type: Generator
+// ----------------------------------------------------------------------------
+
+// Generator generates rawids with its own set of algorithms.
+//
+// Unlike the package-level functions, it does not refer to the variables of
+// the hasher package once created. Which is useful to use more than one set of
+// algorithms at the same time.
+type Generator struct {
+	conf          hasher.Config
+	mmapThreshold int64
+	isFast        bool
+}
+
+// New returns a new Generator. By default, it uses the current values of the
+// variables in the hasher package, such as hasher.HashAlgo. Use the options to
+// change them.
+//
+//	Example:
+//	  gen := genrawid.New(genrawid.WithChkSumAlgo(hasher.ChkSumXOR16))
+func New(opts ...Option) *Generator {
+	gen := &Generator{
+		conf:          hasher.NewConfig(),
+		mmapThreshold: MmapThresholdDefault,
+	}
+
+	for _, opt := range opts {
+		opt(gen)
+	}
+
+	return gen
+}
+
+// ----------------------------------------------------------------------------
+//  Options
+// ----------------------------------------------------------------------------
+
+// Option is the functional option type to configure the Generator.
+type Option func(*Generator)
+
+// WithChkSumAlgo sets the checksum algorithm to use.
+//
+// The rawid consists of the checksum in the bottom and the hash in the rest. So
+// the shorter the checksum is, the more bits of the hash remain in the rawid.
+// E.g. 4 Bytes of hash with CRC32 or xxHash, 6 Bytes with XOR16 and 7 Bytes with
+// XOR8.
+func WithChkSumAlgo(algo hasher.TChkSumAlgo) Option {
+	return func(g *Generator) {
+		g.conf.ChkSumAlgo = algo
+	}
+}
+
+// WithConcurrency sets the number of goroutines to compute the BLAKE3 hash of
+// large inputs. 0 or 1 computes sequentially and a negative value uses the
+// number of CPUs. The rawids are the same regardless of this value.
+//
+// It is worth it for large files on machines with many cores. The sequential
+// one is used unless 6 or more goroutines can run at once, since it is faster
+// than fewer of them. See the hasher.Concurrency variable for details.
+func WithConcurrency(numWorkers int) Option {
+	return func(g *Generator) {
+		g.conf.Concurrency = numWorkers
+	}
+}
+
+// WithContext sets the context string to use the derive-key mode of BLAKE3.
+// The rawids of the same input differ for each context, which is useful to
+// separate the ID spaces of different kinds of objects.
+//
+//	Example:
+//	  gen := genrawid.New(genrawid.WithContext("acme 2026 manifest v1"))
+//
+// The context is a part of the scheme name. See Generator.Scheme. It is an error
+// to use it with the hash algorithm other than BLAKE3, including the fast mode.
+func WithContext(context string) Option {
+	return func(g *Generator) {
+		g.conf.Context = context
+	}
+}
+
+// WithCRC32Poly sets the polynomial to use if the checksum algorithm is CRC32.
+func WithCRC32Poly(poly uint32) Option {
+	return func(g *Generator) {
+		g.conf.CRC32Poly = poly
+	}
+}
+
+// WithFastMode enables or disables the fast mode.
+//
+// In fast mode, the input is hashed only once with XXH64 and its 8 Byte digest
+// is used as the rawid as is. No checksum is calculated, so the other options
+// are ignored. Note that the rawids differ from the regular ones. See SchemeFast.
+func WithFastMode(isFast bool) Option {
+	return func(g *Generator) {
+		g.isFast = isFast
+	}
+}
+
+// WithHashAlgo sets the hash algorithm to use.
+func WithHashAlgo(algo hasher.THashAlgo) Option {
+	return func(g *Generator) {
+		g.conf.HashAlgo = algo
+	}
+}
+
+// WithHashLen sets the byte length of the hash digest to compute the checksum.
+func WithHashLen(lenHash int) Option {
+	return func(g *Generator) {
+		g.conf.HashLen = lenHash
+	}
+}
+
+// ----------------------------------------------------------------------------
+//  Methods
+// ----------------------------------------------------------------------------
+
+// FromFile returns the rawid generated from the input file.
+//
+// Large files are memory-mapped and hashed directly. See WithMmapThreshold.
+func (g *Generator) FromFile(path string) (rawid.ID, error) {
+	result, err := g.ComputeFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return result.ID, nil
+}
+
+// FromReader returns the rawid generated from the input reader.
+func (g *Generator) FromReader(input io.Reader) (rawid.ID, error) {
+	result, err := g.Compute(input)
+	if err != nil {
+		return nil, err
+	}
+
+	return result.ID, nil
+}
+
+// FromStdin returns the rawid generated from stdin as its input.
+func (g *Generator) FromStdin() (rawid.ID, error) {
+	return g.FromReader(OsStdin)
+}
+
+// FromString returns the rawid generated from the input string.
+func (g *Generator) FromString(input string) (rawid.ID, error) {
+	return g.FromReader(strings.NewReader(input))
+}
+
+// ----------------------------------------------------------------------------
+//  Methods (Private)
+// ----------------------------------------------------------------------------
+
+// It returns the Config to compute the hash. In fast mode, it is XXH64 of
+// 8 Bytes, which digest is the rawid as is.
+func (g *Generator) hashConfig() hasher.Config {
+	const lenByteFast = 8
+
+	if !g.isFast {
+		return g.conf
+	}
+
+	return hasher.Config{
+		HashAlgo: hasher.HashAlgoXXH64,
+		HashLen:  lenByteFast,
+		Context:  g.conf.Context, // to be an error since XXH64 does not support it
+	}
+}
+
+// It returns the Result from the hash digest of the input. It computes the
+// checksum of the digest and combines them as a rawid.
+func (g *Generator) newResult(digest []byte, size int64) (Result, error) {
+	if g.isFast {
+		return Result{
+			ID:     digest,
+			Digest: append([]byte{}, digest...),
+			Size:   size,
+			Scheme: SchemeFast,
+		}, nil
+	}
+
+	// Calculate checksum of the hash.
+	sumByte, err := g.conf.CheckSum(bytes.NewReader(digest))
+	if err != nil {
+		return Result{}, errors.Wrap(err, "failed to generate rawid")
+	}
+
+	// Combine the hash and the checksum as a rawid.
+	id, err := chopAndMergeBytes(digest, sumByte)
+	if err != nil {
+		return Result{}, err
+	}
+
+	return Result{
+		ID:       id,
+		Digest:   digest,
+		CheckSum: sumByte,
+		Size:     size,
+		Scheme:   g.Scheme(),
+	}, nil
+}
@@ -0,0 +1,148 @@
+package genrawid
+
+import (
+	"hash/crc32"
+	"os"
+	"testing"
+
+	"github.com/KEINOS/go-genrawid/pkg/hasher"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew_options(t *testing.T) {
+	t.Parallel()
+
+	gen := New(
+		WithHashAlgo(hasher.HashAlgoSHA3_512),
+		WithHashLen(32),
+		WithChkSumAlgo(hasher.ChkSumXOR8),
+		WithCRC32Poly(crc32.Koopman),
+		WithConcurrency(4),
+	)
+
+	assert.Equal(t, hasher.HashAlgoSHA3_512, gen.conf.HashAlgo)
+	assert.Equal(t, 32, gen.conf.HashLen)
+	assert.Equal(t, hasher.ChkSumXOR8, gen.conf.ChkSumAlgo)
+	assert.Equal(t, uint32(crc32.Koopman), gen.conf.CRC32Poly)
+	assert.Equal(t, 4, gen.conf.Concurrency)
+}
+
+func TestGenerator_FromFile_concurrency(t *testing.T) {
+	t.Parallel()
+
+	// The rawid must be the same regardless of the concurrency. Both memory-mapped
+	// and streamed.
+	for _, numWorkers := range []int{0, 1, 2, 3, -1} {
+		for _, threshold := range []int64{0, -1} {
+			gen := New(WithConcurrency(numWorkers), WithMmapThreshold(threshold))
+
+			rawid, err := gen.FromFile("testdata/dummy.bin")
+			require.NoError(t, err)
+
+			assert.Equal(t, "-2929669798473946006", rawid.Dec(),
+				"concurrency: %d, threshold: %d", numWorkers, threshold)
+		}
+	}
+}
+
+func TestGenerator_FromString_checksum_algorithms(t *testing.T) {
+	t.Parallel()
+
+	const input = "abcdefgh"
+
+	for _, test := range []struct {
+		algo   hasher.TChkSumAlgo
+		expect string
+	}{
+		{hasher.ChkSumCRC32, "ddaa2ac39b79058a"},
+		{hasher.ChkSumXXHash, "ddaa2ac32744aeae"},
+		{hasher.ChkSumXOR8, "ddaa2ac30a98651b"},
+		{hasher.ChkSumXOR16, "ddaa2ac30a98963b"}, // same as the former fast mode
+	} {
+		id, err := New(WithChkSumAlgo(test.algo)).FromString(input)
+		require.NoError(t, err)
+
+		assert.Equal(t, test.expect, id.Hex(), "checksum algorithm: %s", test.algo)
+	}
+}
+
+func TestGenerator_FromFile_file_not_found(t *testing.T) {
+	t.Parallel()
+
+	id, err := New().FromFile("dummy/unknown/file")
+
+	require.Error(t, err, "it should be an error on file not found")
+	assert.Contains(t, err.Error(), "failed to open file")
+	assert.True(t, errors.Is(err, hasher.ErrRead), "it should be hasher.ErrRead")
+	assert.True(t, errors.Is(err, os.ErrNotExist), "it should keep the error of the file")
+	assert.Nil(t, id, "rawid should be nil on error")
+}
+
+func TestGenerator_FromReader_unknown_hash_algo(t *testing.T) {
+	t.Parallel()
+
+	id, err := New(WithHashAlgo(hasher.HashAlgoUnknown)).FromString("sample input")
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to generate rawid")
+	assert.Contains(t, err.Error(), "unknown hash algorithm")
+	assert.True(t, errors.Is(err, hasher.ErrUnknownAlgorithm), "it should be hasher.ErrUnknownAlgorithm")
+	assert.Nil(t, id, "rawid should be nil on error")
+}
+
+func TestGenerator_FromString_invalid_hash_length(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		expect hasher.ErrInvalidLength
+		opts   []Option
+	}{
+		// Out of the range of the algorithm
+		{hasher.ErrInvalidLength{Given: 65, Min: 1, Max: 64}, []Option{WithHashAlgo(hasher.HashAlgoSHA3_512), WithHashLen(65)}},
+		// Too short to fill the rawid with the checksum
+		{hasher.ErrInvalidLength{Given: 2, Min: 4}, []Option{WithHashLen(2)}},
+		{hasher.ErrInvalidLength{Given: 6, Min: 7}, []Option{WithHashLen(6), WithChkSumAlgo(hasher.ChkSumXOR8)}},
+	} {
+		id, err := New(test.opts...).FromString("sample input")
+
+		var errLen *hasher.ErrInvalidLength
+
+		require.True(t, errors.As(err, &errLen), "it should be hasher.ErrInvalidLength: %v", err)
+		assert.Equal(t, test.expect, *errLen)
+		assert.Nil(t, id, "rawid should be nil on error")
+	}
+}
+
+func TestGenerator_FromFile_context(t *testing.T) {
+	t.Parallel()
+
+	rawid, err := New(WithContext("acme 2026 manifest v1")).FromFile("testdata/msg.txt")
+	require.NoError(t, err)
+
+	assert.Equal(t, "3728148194611179052", rawid.Dec())
+
+	// The rawid differs from the one without the context or of other contexts
+	for _, context := range []string{"", "acme 2026 manifest v2"} {
+		other, err := New(WithContext(context)).FromFile("testdata/msg.txt")
+		require.NoError(t, err)
+
+		assert.NotEqual(t, rawid.Dec(), other.Dec(), "context: %q", context)
+	}
+}
+
+func TestGenerator_FromString_context_not_supported(t *testing.T) {
+	t.Parallel()
+
+	for _, opts := range [][]Option{
+		{WithContext("foo"), WithHashAlgo(hasher.HashAlgoSHA3_512)},
+		{WithContext("foo"), WithFastMode(true)},
+	} {
+		id, err := New(opts...).FromString("sample input")
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "context is supported only by BLAKE3")
+		assert.Nil(t, id, "rawid should be nil on error")
+	}
+}
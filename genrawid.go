@@ -2,11 +2,15 @@
 Package genrawid provides functions to generate rawid.
 
 For the sample implementation see: ./cmd/genrawid/main.go
+
+The errors can be inspected with errors.Is and errors.As. Such as
+hasher.ErrUnknownAlgorithm, *hasher.ErrInvalidLength and hasher.ErrRead, which
+also keeps the error of the input such as fs.ErrNotExist.
 */
 package genrawid
 
 import (
-	"bytes"
+	"fmt"
 	"io"
 	"os"
 	"strings"
@@ -23,8 +27,12 @@ import (
 // OsStdin is a copy of os.Stdin to ease testing. Mock this variable during tests.
 var OsStdin = os.Stdin
 
-// IsModeFast is the flag to use fast mode. It will use the last 16bit of the hash
-// as the xor16 checksum.
+// IsModeFast is the flag to use fast mode. It will hash the input only once with
+// XXH64 and use its 8 Byte digest as the rawid. See WithFastMode option and
+// SchemeFast for details.
+//
+// Note that the former fast mode, which used the last 16bit of the hash as the
+// xor16 checksum, is equivalent to use hasher.ChkSumXOR16 as the checksum.
 var IsModeFast = false
 
 // ----------------------------------------------------------------------------
@@ -33,14 +41,7 @@ var IsModeFast = false
 
 // FromFile returns the rawid generated from the input file.
 func FromFile(path string) (rawid.ID, error) {
-	file, err := os.Open(path)
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to open file")
-	}
-
-	defer file.Close()
-
-	return genRawid(file)
+	return newFromGlobals().FromFile(path)
 }
 
 // FromStdin returns the rawid generated from stdin as its input.
@@ -59,83 +60,51 @@ func FromString(input string) (rawid.ID, error) {
 //  Functions (Private)
 // ----------------------------------------------------------------------------
 
-// It combines the two input as one in 8 byte length.
+// It combines the hash and the checksum as one in 8 byte length. The checksum
+// takes the bottom bytes, up to 4 bytes, and the hash fills the rest.
 //
 //nolint:varnamelen // allow short variable names for readability.
 func chopAndMergeBytes(a, b []byte) (rawid.ID, error) {
-	if len(a) < 4 || len(b) < 4 {
-		return nil, errors.New("failed to combine bytes. Both of the input must be 4byte or more")
-	}
-
-	lenByte := 8
-	rawid := make([]byte, lenByte)
-
-	copy(rawid, a)     // Upper half as hash
-	copy(rawid[4:], b) // Bottom half as checksum
-
-	return rawid, nil
-}
-
-// It returns 64bit/8byte length rawid.
-func genRawid(input io.Reader) (rawid.ID, error) {
-	// Calculate hash value.
-	hashByte, err := hasher.Hash(input)
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to generate rawid")
+	const (
+		lenByte   = 8
+		lenSumMax = 4
+	)
+
+	lenSum := len(b)
+	if lenSum > lenSumMax {
+		lenSum = lenSumMax
 	}
 
-	// Calculate checksum of the hash.
-	if !IsModeFast {
-		r := bytes.NewReader(hashByte)
+	if lenSum == 0 || len(a) < lenByte-lenSum {
+		msg := fmt.Sprintf(
+			"failed to combine bytes. The checksum must be 1byte or more and the hash must fill the rest. hash: %d byte, checksum: %d byte",
+			len(a),
+			len(b),
+		)
 
-		sumByte, err := hasher.CheckSum(r)
-		if err != nil {
-			return nil, errors.Wrap(err, "failed to generate rawid")
+		if lenSum == 0 {
+			return nil, errors.New(msg)
 		}
 
-		// Combine the fisrt 4 Bytes of the hash and the checksum as a rawid.
-		return chopAndMergeBytes(hashByte, sumByte)
+		// The hash is too short to fill the rest of the checksum
+		return nil, errors.Wrap(&hasher.ErrInvalidLength{Given: len(a), Min: lenByte - lenSum}, msg)
 	}
 
-	// ------------------------------------------------------------------------
-	// Fast mode: use the last 16 bit/2 Bytes of the hash as the xor16 checksum.
-	// Important:
-	//   This mode is currently hidden since it was not fast enough as expected.
-	// ------------------------------------------------------------------------
-
-	// Calculate the xor16 checksum of the hash.
-	chkSum := xorSliceByte(hashByte)
-	lenByte := 8
 	rawid := make([]byte, lenByte)
 
-	// Set hash
-	copy(rawid, hashByte)
+	copy(rawid, a[:lenByte-lenSum])          // Upper bytes as hash
+	copy(rawid[lenByte-lenSum:], b[:lenSum]) // Bottom bytes as checksum
 
-	// Set the last 2 bytes(16bit) of the hash as the checksum.
-	return replaceLast16bit(rawid, chkSum), nil
+	return rawid, nil
 }
 
-func replaceLast16bit(input []byte, xor16 uint16) []byte {
-	lenBitShift := 8
-
-	input[len(input)-2] = byte(xor16 >> lenBitShift)
-	input[len(input)-1] = byte(xor16)
-
-	return input
+// It returns 64bit/8byte length rawid using the current settings of the package
+// variables.
+func genRawid(input io.Reader) (rawid.ID, error) {
+	return newFromGlobals().FromReader(input)
 }
 
-func xorSliceByte(input []byte) uint16 {
-	lenBitShift := 8
-
-	var out uint16
-
-	for i, b := range input {
-		if i%2 == 0 {
-			out ^= uint16(b)
-		} else {
-			out ^= uint16(b) << lenBitShift
-		}
-	}
-
-	return out
+// It returns a new Generator with the current settings of the package variables.
+func newFromGlobals() *Generator {
+	return New(WithFastMode(IsModeFast))
 }
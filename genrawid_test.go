@@ -80,6 +80,22 @@ func Test_chopAndMergeBytes_golden(t *testing.T) {
 	assert.Equal(t, expect, actual)
 }
 
+func Test_chopAndMergeBytes_short_checksum(t *testing.T) {
+	t.Parallel()
+
+	a := []byte{0x1, 0x2, 0x3, 0x4, 0x5, 0x6, 0x7, 0x8}
+	b := []byte{0x9, 0xA}
+
+	// Merge the first 6 bytes of a and 2 bytes of b.
+	rawid, err := chopAndMergeBytes(a, b)
+
+	require.NoError(t, err)
+
+	expect := []byte{0x1, 0x2, 0x3, 0x4, 0x5, 0x6, 0x9, 0xA}
+	actual := rawid.Byte()
+	assert.Equal(t, expect, actual)
+}
+
 func Test_chopAndMergeBytes_too_few_slice(t *testing.T) {
 	t.Parallel()
 
@@ -90,17 +106,17 @@ func Test_chopAndMergeBytes_too_few_slice(t *testing.T) {
 		rawid, err := chopAndMergeBytes(a, b)
 
 		require.Error(t, err)
-		assert.Contains(t, err.Error(), "failed to combine bytes. Both of the input must be 4byte or more")
+		assert.Contains(t, err.Error(), "failed to combine bytes. The checksum must be 1byte or more")
 		assert.Nil(t, rawid, "on error the returned rawid should be nil")
 	}
 	{
 		a := []byte{0x1, 0x2, 0x3, 0x4, 0x5, 0x6}
-		b := []byte{0x9, 0xA, 0xB}
+		b := []byte{}
 
 		rawid, err := chopAndMergeBytes(a, b)
 
 		require.Error(t, err)
-		assert.Contains(t, err.Error(), "failed to combine bytes. Both of the input must be 4byte or more")
+		assert.Contains(t, err.Error(), "failed to combine bytes. The checksum must be 1byte or more")
 		assert.Nil(t, rawid, "on error the returned rawid should be nil")
 	}
 }
@@ -135,26 +151,3 @@ func Test_genRawid_use_undefined_algo(t *testing.T) {
 	assert.Contains(t, err.Error(), "failed to generate rawid")
 	assert.Nil(t, rawid)
 }
-
-func Test_replaceLast16bit(t *testing.T) {
-	t.Parallel()
-
-	inHash := []byte{0x01, 0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08}
-	inChkSum := uint16(0xffff)
-
-	expect := []byte{0x01, 0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0xff, 0xff}
-	actual := replaceLast16bit(inHash, inChkSum)
-
-	assert.Equal(t, expect, actual, "the last 2 bytes of the rawid should be replaced with the checksum")
-}
-
-func Test_xorSliceByte(t *testing.T) {
-	t.Parallel()
-
-	input := []byte{0x01, 0x01, 0x02, 0x03, 0x04, 0x05}
-
-	expect := uint16(0x0707) // 0x01 ^ 0x02 ^ 0x4 = 0x07, 0x01 ^ 0x03 ^ 0x5 = 0x07
-	actual := xorSliceByte(input)
-
-	assert.Equal(t, expect, actual)
-}
@@ -0,0 +1,82 @@
+package genrawid
+
+import (
+	"bytes"
+	"os"
+)
+
+// ----------------------------------------------------------------------------
+//  Constants and Variables
+// ----------------------------------------------------------------------------
+
+// MmapThresholdDefault is the default file size in bytes to use the memory-
+// mapped file in Generator.FromFile. Which is 64 MiB.
+const MmapThresholdDefault = int64(64 * 1024 * 1024)
+
+// fnMmap is a copy of mmap to ease testing.
+var fnMmap = mmap
+
+// ----------------------------------------------------------------------------
+//  Options
+// ----------------------------------------------------------------------------
+
+// WithMmapThreshold sets the file size in bytes to hash the file via memory-
+// mapping (mmap) in FromFile method. By default it is MmapThresholdDefault.
+//
+// Files of the given size or larger are mapped to the memory and hashed directly
+// without copying to buffers. Set 0 to always map and a negative value to never
+// map the file.
+//
+// It falls back to read the file as a stream if the file is not a regular file,
+// such as pipes and devices, or the OS does not support or failed to map it.
+//
+// Note that the file must not be truncated while hashing. Since accessing the
+// truncated region of a mapped file crashes the program with SIGBUS.
+func WithMmapThreshold(size int64) Option {
+	return func(g *Generator) {
+		g.mmapThreshold = size
+	}
+}
+
+// ----------------------------------------------------------------------------
+//  Methods (Private)
+// ----------------------------------------------------------------------------
+
+// It returns the Result of the opened file. It maps the file to the memory if
+// the file size is equal or larger than the threshold. Otherwise or on failure
+// it reads the file as a stream.
+func (g *Generator) computeOsFile(file *os.File) (Result, error) {
+	if data, unmap, ok := g.tryMmap(file); ok {
+		//nolint:errcheck // unmapping a read-only mapping never fails in practice
+		defer unmap()
+
+		return g.Compute(bytes.NewReader(data))
+	}
+
+	return g.Compute(file)
+}
+
+// It maps the file to the memory if possible.
+func (g *Generator) tryMmap(file *os.File) ([]byte, func() error, bool) {
+	if g.mmapThreshold < 0 {
+		return nil, nil, false
+	}
+
+	info, err := file.Stat()
+	if err != nil || !info.Mode().IsRegular() {
+		return nil, nil, false
+	}
+
+	// Empty files can not be mapped.
+	size := info.Size()
+	if size == 0 || size < g.mmapThreshold {
+		return nil, nil, false
+	}
+
+	data, unmap, err := fnMmap(file, size)
+	if err != nil {
+		return nil, nil, false
+	}
+
+	return data, unmap, true
+}
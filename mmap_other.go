@@ -0,0 +1,16 @@
+//go:build !(darwin || dragonfly || freebsd || linux || netbsd || openbsd)
+// +build !darwin,!dragonfly,!freebsd,!linux,!netbsd,!openbsd
+
+package genrawid
+
+import (
+	"os"
+
+	"github.com/pkg/errors"
+)
+
+// It always returns an error since mapping files is not supported on this OS.
+// The file is read as a stream instead.
+func mmap(file *os.File, size int64) ([]byte, func() error, error) {
+	return nil, nil, errors.New("memory-mapped file is not supported on this OS")
+}
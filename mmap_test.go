@@ -0,0 +1,99 @@
+package genrawid
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+//nolint:paralleltest // do not parallelize due to mocking fnMmap
+func TestGenerator_FromFile_mmap(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("memory-mapped file is not supported on Windows")
+	}
+
+	pathFile := "testdata/dummy.bin"
+
+	for _, test := range []struct {
+		threshold int64
+		isMapped  bool
+	}{
+		{threshold: 0, isMapped: true},
+		{threshold: 5e6, isMapped: true}, // same size as dummy.bin
+		{threshold: 5e6 + 1, isMapped: false},
+		{threshold: -1, isMapped: false},
+	} {
+		isMapped := spyMmap(t)
+
+		rawid, err := New(WithMmapThreshold(test.threshold)).FromFile(pathFile)
+		require.NoError(t, err)
+
+		assert.Equal(t, "-2929669798473946006", rawid.Dec(), "threshold: %d", test.threshold)
+		assert.Equal(t, test.isMapped, *isMapped, "threshold: %d", test.threshold)
+	}
+}
+
+//nolint:paralleltest // do not parallelize due to mocking fnMmap
+func TestGenerator_FromFile_mmap_fail(t *testing.T) {
+	oldFnMmap := fnMmap
+	defer func() {
+		fnMmap = oldFnMmap
+	}()
+
+	fnMmap = func(file *os.File, size int64) ([]byte, func() error, error) {
+		return nil, nil, errors.New("forced error")
+	}
+
+	// It should fall back to read the file as a stream
+	rawid, err := New(WithMmapThreshold(0)).FromFile("testdata/dummy.bin")
+	require.NoError(t, err)
+
+	assert.Equal(t, "-2929669798473946006", rawid.Dec())
+}
+
+//nolint:paralleltest // do not parallelize due to mocking fnMmap
+func TestGenerator_FromFile_mmap_not_regular_or_empty(t *testing.T) {
+	pathEmpty := filepath.Join(t.TempDir(), "empty.txt")
+	require.NoError(t, os.WriteFile(pathEmpty, []byte{}, 0o600))
+
+	expect, err := New().FromString("")
+	require.NoError(t, err)
+
+	for _, pathFile := range []string{
+		os.DevNull, // special file
+		pathEmpty,  // empty file can not be mapped
+	} {
+		isMapped := spyMmap(t)
+
+		rawid, err := New(WithMmapThreshold(0)).FromFile(pathFile)
+		require.NoError(t, err)
+
+		assert.Equal(t, expect, rawid, "file: %s", pathFile)
+		assert.False(t, *isMapped, "file: %s", pathFile)
+	}
+}
+
+// It wraps fnMmap to tell if it was called. The original is restored on cleanup.
+func spyMmap(t *testing.T) *bool {
+	t.Helper()
+
+	oldFnMmap := fnMmap
+	isCalled := false
+
+	fnMmap = func(file *os.File, size int64) ([]byte, func() error, error) {
+		isCalled = true
+
+		return oldFnMmap(file, size)
+	}
+
+	t.Cleanup(func() {
+		fnMmap = oldFnMmap
+	})
+
+	return &isCalled
+}
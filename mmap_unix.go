@@ -0,0 +1,27 @@
+//go:build darwin || dragonfly || freebsd || linux || netbsd || openbsd
+// +build darwin dragonfly freebsd linux netbsd openbsd
+
+package genrawid
+
+import (
+	"os"
+	"syscall"
+
+	"github.com/pkg/errors"
+)
+
+// It maps the file of the given size to the memory as read-only. The returned
+// function unmaps it.
+func mmap(file *os.File, size int64) ([]byte, func() error, error) {
+	// The size must fit in int. E.g. files over 2 GiB on 32 bit systems.
+	if int64(int(size)) != size {
+		return nil, nil, errors.Errorf("file too large to map: %d bytes", size)
+	}
+
+	data, err := syscall.Mmap(int(file.Fd()), 0, int(size), syscall.PROT_READ, syscall.MAP_SHARED)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to map file")
+	}
+
+	return data, func() error { return syscall.Munmap(data) }, nil
+}
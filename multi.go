@@ -0,0 +1,61 @@
+package genrawid
+
+import (
+	"io"
+
+	"github.com/KEINOS/go-genrawid/pkg/hasher"
+	"github.com/pkg/errors"
+)
+
+// ----------------------------------------------------------------------------
+//  Functions (Public)
+// ----------------------------------------------------------------------------
+
+// ComputeMulti returns the Results of the input for each of the Generators, in
+// the same order. The input is read only once and written to the hashers of all
+// the Generators at the same time.
+//
+// It is useful to compute the rawids of more than one scheme, such as migrating
+// the rawids from one scheme to another. See ParseScheme.
+func ComputeMulti(input io.Reader, gens ...*Generator) ([]Result, error) {
+	if input == nil {
+		return nil, errors.New("nil pointer for input given")
+	}
+
+	if len(gens) == 0 {
+		return nil, errors.New("no generator given")
+	}
+
+	digesters := make([]hasher.Digester, len(gens))
+	writers := make([]io.Writer, len(gens))
+
+	for i, gen := range gens {
+		digest, err := gen.hashConfig().NewDigester()
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to generate rawid of scheme %s", gen.Scheme())
+		}
+
+		digesters[i], writers[i] = digest, digest
+	}
+
+	// The digesters never fail to write. So the error is of the input.
+	size, err := io.Copy(io.MultiWriter(writers...), input)
+	if err != nil {
+		return nil, hasher.NewErrRead(err)
+	}
+
+	results := make([]Result, len(gens))
+
+	for i, gen := range gens {
+		digest, err := digesters[i].Sum()
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to generate rawid of scheme %s", gen.Scheme())
+		}
+
+		if results[i], err = gen.newResult(digest, size); err != nil {
+			return nil, errors.Wrapf(err, "failed to generate rawid of scheme %s", gen.Scheme())
+		}
+	}
+
+	return results, nil
+}
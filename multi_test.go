@@ -0,0 +1,87 @@
+package genrawid
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/KEINOS/go-genrawid/pkg/hasher"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestComputeMulti(t *testing.T) {
+	t.Parallel()
+
+	const input = "abcd\r\nefgh\n"
+
+	gens := []*Generator{
+		New(WithHashAlgo(hasher.HashAlgoSHA3_512)),
+		New(),
+		New(WithFastMode(true)),
+		New(WithHashLen(32), WithChkSumAlgo(hasher.ChkSumXOR8), WithConcurrency(2)),
+	}
+
+	results, err := ComputeMulti(strings.NewReader(input), gens...)
+	require.NoError(t, err)
+	require.Len(t, results, len(gens))
+
+	// It must be the same as computing one by one
+	for i, gen := range gens {
+		expect, err := gen.Compute(strings.NewReader(input))
+		require.NoError(t, err)
+
+		assert.Equal(t, expect, results[i], "scheme: %s", gen.Scheme())
+	}
+
+	assert.Equal(t, "ddaa2ac39b79058a", results[1].ID.Hex())
+	assert.Equal(t, "v1/sha3-512/512/crc32c", results[0].Scheme)
+}
+
+func TestComputeMulti_error(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		input  io.Reader
+		expect string
+		gens   []*Generator
+	}{
+		{
+			gens:   []*Generator{New()},
+			expect: "nil pointer for input given",
+		},
+		{
+			input:  strings.NewReader("foo"),
+			expect: "no generator given",
+		},
+		{
+			input:  strings.NewReader("foo"),
+			gens:   []*Generator{New(), New(WithHashAlgo(hasher.HashAlgoUnknown))},
+			expect: "unknown hash algorithm",
+		},
+		{
+			input:  strings.NewReader("foo"),
+			gens:   []*Generator{New(), New(WithChkSumAlgo(hasher.ChkSumUnknown))},
+			expect: "unknown checksum algorithm",
+		},
+	} {
+		results, err := ComputeMulti(test.input, test.gens...)
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), test.expect)
+		assert.Nil(t, results, "it should be nil on error")
+	}
+}
+
+func TestComputeMulti_read_error(t *testing.T) {
+	t.Parallel()
+
+	results, err := ComputeMulti(iotest.ErrReader(errors.New("forced error")), New(), New(WithFastMode(true)))
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to read input")
+	assert.Contains(t, err.Error(), "forced error")
+	assert.Nil(t, results)
+}
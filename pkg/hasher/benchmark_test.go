@@ -5,10 +5,14 @@ package hasher_test
 // ============================================================================
 
 import (
+	"hash/crc32"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
 	"github.com/KEINOS/go-genrawid/pkg/hasher"
+	"github.com/stretchr/testify/require"
 )
 
 // ----------------------------------------------------------------------------
@@ -73,3 +77,84 @@ func BenchmarkHashSHA3(b *testing.B) {
 		_, _ = hasher.Hash(r)
 	}
 }
+
+// ----------------------------------------------------------------------------
+//  Streaming of large inputs
+// ----------------------------------------------------------------------------
+//  Current conclusion: Reading the input into pooled 128 KiB buffers is faster
+//  for all the algorithms than the former implementations which read the input
+//  line by line (BLAKE3, SHA3-512), with io.Copy (CRC32) or byte by byte (xxHash,
+//  XOR8). The digests are the same as before.
+//
+//    goos: linux
+//    goarch: amd64
+//    cpu: Intel(R) Xeon(R) Processor
+//
+//    Before:
+//    BenchmarkStream/blake3     20     57551411 ns/op   1166.07 MB/s   4312 B/op   5 allocs/op
+//    BenchmarkStream/sha3-512    2    576324498 ns/op    116.44 MB/s   5272 B/op   8 allocs/op
+//    BenchmarkStream/xxh64      64     18151796 ns/op   3697.09 MB/s  37112 B/op   8 allocs/op
+//    BenchmarkStream/crc32      73     16033089 ns/op   4185.65 MB/s  32944 B/op   6 allocs/op
+//    BenchmarkStream/xxhash     61     18756177 ns/op   3577.96 MB/s  37112 B/op   8 allocs/op
+//    BenchmarkStream/xor8        1  31924410413 ns/op      2.10 MB/s    360 B/op   7 allocs/op
+//    BenchmarkStream/xor16      10    108324926 ns/op    619.51 MB/s   4251 B/op   5 allocs/op
+//
+//    After:
+//    BenchmarkStream/blake3     31     42673428 ns/op   1572.61 MB/s  11166 B/op  10 allocs/op
+//    BenchmarkStream/sha3-512    2    523621862 ns/op    128.16 MB/s   1324 B/op  13 allocs/op
+//    BenchmarkStream/xxh64      87     14136449 ns/op   4747.22 MB/s    273 B/op   7 allocs/op
+//    BenchmarkStream/crc32     138      7905004 ns/op   8489.42 MB/s    208 B/op   7 allocs/op
+//    BenchmarkStream/xxhash    100     10912147 ns/op   6149.92 MB/s    273 B/op   7 allocs/op
+//    BenchmarkStream/xor8       37     34008676 ns/op   1973.29 MB/s    325 B/op   6 allocs/op
+//    BenchmarkStream/xor16     129      9048180 ns/op   7416.84 MB/s    187 B/op   6 allocs/op
+//
+//  The parallel BLAKE3 is a pure Go implementation. Which is about 5 times
+//  slower per core than the sequential one with SIMD instructions, so it is not
+//  used with fewer cores. See BenchmarkBlake3Parallel for the crossover.
+// ----------------------------------------------------------------------------
+
+func BenchmarkStream(b *testing.B) {
+	const sizeFile = 64 * 1024 * 1024 // 64MiB
+
+	pathFile := filepath.Join(b.TempDir(), "data.bin")
+
+	data := make([]byte, sizeFile)
+	for i := range data {
+		data[i] = byte(i % 251)
+	}
+
+	require.NoError(b, os.WriteFile(pathFile, data, 0o600))
+
+	for _, test := range []struct {
+		conf hasher.Config
+		name string
+	}{
+		{name: "blake3", conf: hasher.Config{HashAlgo: hasher.HashAlgoBLAKE3}},
+		{name: "sha3-512", conf: hasher.Config{HashAlgo: hasher.HashAlgoSHA3_512}},
+		{name: "xxh64", conf: hasher.Config{HashAlgo: hasher.HashAlgoXXH64}},
+		{name: "crc32", conf: hasher.Config{ChkSumAlgo: hasher.ChkSumCRC32, CRC32Poly: crc32.Castagnoli}},
+		{name: "xxhash", conf: hasher.Config{ChkSumAlgo: hasher.ChkSumXXHash}},
+		{name: "xor8", conf: hasher.Config{ChkSumAlgo: hasher.ChkSumXOR8}},
+		{name: "xor16", conf: hasher.Config{ChkSumAlgo: hasher.ChkSumXOR16}},
+	} {
+		conf := test.conf
+
+		b.Run(test.name, func(b *testing.B) {
+			b.SetBytes(sizeFile)
+
+			for i := 0; i < b.N; i++ {
+				osFile, err := os.Open(pathFile)
+				require.NoError(b, err)
+
+				if conf.ChkSumAlgo == hasher.ChkSumUnknown {
+					_, err = conf.Hash(osFile)
+				} else {
+					_, err = conf.CheckSum(osFile)
+				}
+
+				require.NoError(b, err)
+				require.NoError(b, osFile.Close())
+			}
+		})
+	}
+}
@@ -1,7 +1,6 @@
 package hasher
 
 import (
-	"bufio"
 	"io"
 
 	"github.com/pkg/errors"
@@ -16,7 +15,31 @@ var IoSeekStart = io.SeekStart
 //
 // It uses github.com/zeebo/blake3 package for BLAKE3 algorithm implementation
 // of Go. This algorithm can generate a digest from 1 up to 8194 bytes of length.
+//
+// Note that the line breaks of the input are ignored. See lineBreakStripper.
 func _blake3(input io.Reader, lenOut int) ([]byte, error) {
+	digest, err := newBlake3(lenOut, "")
+	if err != nil {
+		return nil, err
+	}
+
+	return stream(digest, input)
+}
+
+// ----------------------------------------------------------------------------
+//  Type: blake3Digester
+// ----------------------------------------------------------------------------
+
+// blake3Digester is the digester of BLAKE3.
+type blake3Digester struct {
+	hasher   *blake3.Hasher
+	stripper *lineBreakStripper
+	lenOut   int
+}
+
+// It returns the digester of BLAKE3. If context is not empty, it is of the
+// derive-key mode with the context string.
+func newBlake3(lenOut int, context string) (*blake3Digester, error) {
 	lenMax := 8194
 
 	if lenOut == 0 {
@@ -24,31 +47,33 @@ func _blake3(input io.Reader, lenOut int) ([]byte, error) {
 	}
 
 	if lenOut > lenMax || lenOut < 1 {
-		return nil, errors.Errorf(
-			"invalid output length. It must be between 1 and 8194. Given length: %d",
-			lenOut,
-		)
+		return nil, &ErrInvalidLength{Given: lenOut, Min: 1, Max: lenMax}
 	}
 
-	// Create new Hasher that has a digest size of 32 "bytes".
 	blake3Hasher := blake3.New()
-
-	// Read the input data
-	scanner := bufio.NewScanner(input)
-	for scanner.Scan() {
-		// The blake3.Hasher.Write never returns an error
-		_, _ = blake3Hasher.Write(scanner.Bytes())
+	if context != "" {
+		blake3Hasher = blake3.NewDeriveKey(context)
 	}
 
-	if err := scanner.Err(); err != nil {
-		return nil, errors.Wrap(err, "failed to read during scanning")
-	}
+	return &blake3Digester{
+		hasher:   blake3Hasher,
+		stripper: &lineBreakStripper{w: blake3Hasher},
+		lenOut:   lenOut,
+	}, nil
+}
+
+// Write implements io.Writer. It never returns an error.
+func (d *blake3Digester) Write(p []byte) (int, error) {
+	return d.stripper.Write(p)
+}
 
+// Sum returns the digest of lenOut bytes of length.
+func (d *blake3Digester) Sum() ([]byte, error) {
 	// Finalize the hash and return the digest.
 	// Digest takes a snapshot of the hash state and returns an object that can
 	// be used to read and seek through 2^64 bytes of digest output.
-	tmpDigest := blake3Hasher.Digest()
-	hashed := make([]byte, lenOut)
+	tmpDigest := d.hasher.Digest()
+	hashed := make([]byte, d.lenOut)
 
 	if _, err := tmpDigest.Seek(0, IoSeekStart); err != nil {
 		return nil, errors.Wrap(err, "failed to set the position to seek")
@@ -0,0 +1,206 @@
+package hasher
+
+import (
+	"io"
+	"runtime"
+	"sync"
+)
+
+// ----------------------------------------------------------------------------
+//  Constants and Variables
+// ----------------------------------------------------------------------------
+
+// lenBlockParallel is the byte size of the input that a goroutine hashes at once.
+// Which is 1 MiB, 1024 chunks of BLAKE3. It must be a power of 2 number of chunks
+// to be a complete subtree of the BLAKE3 tree.
+const lenBlockParallel = 1024 * blake3LenChunk
+
+// poolBlockParallel is the pool of the buffers to hold the blocks to hash.
+var poolBlockParallel = sync.Pool{
+	New: func() interface{} {
+		buf := make([]byte, 0, lenBlockParallel)
+
+		return &buf
+	},
+}
+
+// The _blake3Parallel returns the BLAKE3 hash of input using up to numWorkers
+// goroutines. If numWorkers is less than 1, it uses the number of CPUs.
+//
+// The digest is identical to _blake3. Inputs of 1 MiB or less are hashed as
+// _blake3 does.
+func _blake3Parallel(input io.Reader, lenOut int, numWorkers int) ([]byte, error) {
+	digest, err := newBlake3Parallel(lenOut, numWorkers, "")
+	if err != nil {
+		return nil, err
+	}
+
+	return stream(digest, input)
+}
+
+// ----------------------------------------------------------------------------
+//  Type: blake3ParallelDigester
+// ----------------------------------------------------------------------------
+
+// blake3ParallelDigester is the digester of BLAKE3 that hashes the input in
+// parallel.
+//
+// The input is split into blocks of lenBlockParallel. Each block is a complete
+// subtree of the BLAKE3 tree, so their chaining values are computed in parallel
+// and merged at the end. The last block is kept until Sum is called, since it
+// can be the root.
+type blake3ParallelDigester struct {
+	stripper *lineBreakStripper
+	blocks   *blake3Blocks
+	context  string
+	lenOut   int
+}
+
+func newBlake3Parallel(lenOut int, numWorkers int, context string) (*blake3ParallelDigester, error) {
+	// Validate the output length the same way as the sequential one
+	if _, err := newBlake3(lenOut, context); err != nil {
+		return nil, err
+	}
+
+	if lenOut == 0 {
+		lenOut = hashLenDefault
+	}
+
+	if numWorkers < 1 {
+		numWorkers = runtime.NumCPU()
+	}
+
+	blocks := &blake3Blocks{
+		mode:      newBlake3Mode(context),
+		semaphore: make(chan struct{}, numWorkers),
+	}
+
+	return &blake3ParallelDigester{
+		stripper: &lineBreakStripper{w: blocks},
+		blocks:   blocks,
+		context:  context,
+		lenOut:   lenOut,
+	}, nil
+}
+
+// Write implements io.Writer. It never returns an error.
+func (d *blake3ParallelDigester) Write(p []byte) (int, error) {
+	return d.stripper.Write(p)
+}
+
+// Sum returns the digest of lenOut bytes of length. It waits for all the
+// blocks written so far to be hashed.
+func (d *blake3ParallelDigester) Sum() ([]byte, error) {
+	blocks := d.blocks
+	blocks.waitGroup.Wait()
+
+	// The input is within a block. Let the sequential one handle it.
+	if len(blocks.cvs) == 0 {
+		digest, err := newBlake3(d.lenOut, d.context)
+		if err != nil {
+			return nil, err
+		}
+
+		_, _ = digest.hasher.Write(blocks.current())
+
+		return digest.Sum()
+	}
+
+	hashed := make([]byte, d.lenOut)
+	cvs := make([][8]uint32, 0, len(blocks.cvs)+1)
+
+	for _, cv := range blocks.cvs {
+		cvs = append(cvs, *cv)
+	}
+
+	mode := &blocks.mode
+	cvs = append(cvs, blake3SubtreeCV(&mode.key, blocks.current(), blocks.counter(), mode.flags))
+
+	root := blake3MergeNode(&mode.key, cvs, mode.flags)
+	root.rootOutput(hashed)
+
+	return hashed, nil
+}
+
+// ----------------------------------------------------------------------------
+//  Type: blake3Blocks
+// ----------------------------------------------------------------------------
+
+// blake3Blocks is an io.Writer that splits the data into blocks and computes
+// the chaining values of the blocks in goroutines.
+type blake3Blocks struct {
+	mode blake3Mode
+	// ptrBuf is the block being filled. It is nil until the first write.
+	ptrBuf *[]byte
+	// cvs are the chaining values of the blocks handed to the goroutines. They
+	// are ready to read after waitGroup.Wait().
+	cvs []*[8]uint32
+	// semaphore limits the number of the goroutines running at the same time.
+	semaphore chan struct{}
+	waitGroup sync.WaitGroup
+}
+
+// Write implements io.Writer. It never returns an error.
+func (b *blake3Blocks) Write(p []byte) (int, error) {
+	lenP := len(p)
+
+	for len(p) > 0 {
+		if b.ptrBuf == nil {
+			b.ptrBuf, _ = poolBlockParallel.Get().(*[]byte)
+			*b.ptrBuf = (*b.ptrBuf)[:0]
+		}
+
+		buf := *b.ptrBuf
+
+		// Hand the full block over only if more data follows. Otherwise it might
+		// be the root.
+		if len(buf) == lenBlockParallel {
+			b.dispatch()
+
+			continue
+		}
+
+		lenCopied := copy(buf[len(buf):lenBlockParallel], p)
+		*b.ptrBuf = buf[:len(buf)+lenCopied]
+		p = p[lenCopied:]
+	}
+
+	return lenP, nil
+}
+
+// It returns the data of the block being filled.
+func (b *blake3Blocks) current() []byte {
+	if b.ptrBuf == nil {
+		return nil
+	}
+
+	return *b.ptrBuf
+}
+
+// It returns the chunk counter of the block being filled.
+func (b *blake3Blocks) counter() uint64 {
+	return uint64(len(b.cvs)) * (lenBlockParallel / blake3LenChunk)
+}
+
+// It computes the chaining value of the full block being filled in a goroutine
+// and releases the buffer to the pool after that.
+func (b *blake3Blocks) dispatch() {
+	ptrBuf, counter := b.ptrBuf, b.counter()
+	chainVal := new([8]uint32)
+
+	b.ptrBuf = nil
+	b.cvs = append(b.cvs, chainVal)
+
+	b.semaphore <- struct{}{} // blocks if all the workers are busy
+	b.waitGroup.Add(1)
+
+	go func() {
+		defer func() {
+			poolBlockParallel.Put(ptrBuf)
+			<-b.semaphore
+			b.waitGroup.Done()
+		}()
+
+		*chainVal = blake3SubtreeCV(&b.mode.key, *ptrBuf, counter, b.mode.flags)
+	}()
+}
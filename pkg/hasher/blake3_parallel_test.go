@@ -0,0 +1,160 @@
+package hasher
+
+import (
+	"bytes"
+	"fmt"
+	"math/rand"
+	"runtime"
+	"testing"
+	"testing/iotest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zeebo/blake3"
+)
+
+func Test_blake3Parallel_same_as_sequential(t *testing.T) {
+	t.Parallel()
+
+	// Random data including line breaks to be stripped
+	data := make([]byte, 5*lenBlockParallel+7)
+	rand.New(rand.NewSource(1)).Read(data)
+
+	for _, size := range []int{
+		0, 1, blake3LenChunk, blake3LenChunk + 1,
+		lenBlockParallel - 1, lenBlockParallel, lenBlockParallel + 1,
+		2 * lenBlockParallel, 3*lenBlockParallel + blake3LenChunk, len(data),
+	} {
+		for _, lenOut := range []int{0, 8, 100} {
+			input := data[:size]
+
+			expect, err := _blake3(bytes.NewReader(input), lenOut)
+			require.NoError(t, err)
+
+			for _, numWorkers := range []int{0, 1, 3} {
+				actual, err := _blake3Parallel(iotest.HalfReader(bytes.NewReader(input)), lenOut, numWorkers)
+
+				require.NoError(t, err)
+				assert.Equal(t, expect, actual,
+					"size: %d, lenOut: %d, workers: %d", size, lenOut, numWorkers)
+			}
+		}
+	}
+}
+
+func Test_blake3Parallel_invalid_lenOut(t *testing.T) {
+	t.Parallel()
+
+	hashed, err := _blake3Parallel(bytes.NewReader([]byte("foo bar")), 8195, 2)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid output length. It must be between 1 and 8194")
+	assert.Nil(t, hashed)
+}
+
+func Test_blake3SubtreeNode_same_as_zeebo(t *testing.T) {
+	t.Parallel()
+
+	data := make([]byte, 9*blake3LenChunk)
+	rand.New(rand.NewSource(2)).Read(data)
+
+	// Sizes not aligned to the blocks of the parallel digester
+	for _, size := range []int{
+		blake3LenChunk + 1, 2 * blake3LenChunk, 3*blake3LenChunk - 1,
+		4*blake3LenChunk + 64, 5 * blake3LenChunk, len(data),
+	} {
+		expect := blake3.Sum512(data[:size])
+
+		root := blake3SubtreeNode(&blake3IV, data[:size], 0, 0)
+		actual := make([]byte, len(expect))
+		root.rootOutput(actual)
+
+		assert.Equal(t, fmt.Sprintf("%x", expect), fmt.Sprintf("%x", actual), "size: %d", size)
+	}
+}
+
+//nolint:paralleltest // do not parallelize due to changing GOMAXPROCS
+func Test_isParallelFaster(t *testing.T) {
+	oldMaxProcs := runtime.GOMAXPROCS(8)
+	defer runtime.GOMAXPROCS(oldMaxProcs)
+
+	for _, test := range []struct {
+		numWorkers int
+		expect     bool
+	}{
+		{1, false},
+		{4, false},
+		{minWorkersParallel - 1, false},
+		{minWorkersParallel, true},
+		{16, true}, // up to GOMAXPROCS run at once
+	} {
+		assert.Equal(t, test.expect, isParallelFaster(test.numWorkers), "workers: %d", test.numWorkers)
+	}
+
+	runtime.GOMAXPROCS(minWorkersParallel - 1)
+
+	assert.False(t, isParallelFaster(16), "it should not be faster with fewer cores than minWorkersParallel")
+}
+
+//nolint:paralleltest // do not parallelize due to changing GOMAXPROCS
+func TestConfig_NewDigester_parallel(t *testing.T) {
+	oldMaxProcs := runtime.GOMAXPROCS(minWorkersParallel)
+	defer runtime.GOMAXPROCS(oldMaxProcs)
+
+	for _, test := range []struct {
+		numWorkers int
+		isParallel bool
+	}{
+		{2, false}, // slower than the sequential one
+		{minWorkersParallel, true},
+	} {
+		digest, err := Config{HashAlgo: HashAlgoBLAKE3, Concurrency: test.numWorkers}.NewDigester()
+		require.NoError(t, err)
+
+		_, isParallel := digest.(*blake3ParallelDigester)
+		assert.Equal(t, test.isParallel, isParallel, "workers: %d", test.numWorkers)
+	}
+}
+
+// ----------------------------------------------------------------------------
+//  Benchmarks
+// ----------------------------------------------------------------------------
+//  The parallel one runs GOMAXPROCS goroutines. To find the crossover against
+//  the sequential one, run with the numbers of the cores to compare:
+//
+//    go test -run NONE -bench Blake3Parallel -cpu 1,2,4,6,8 ./pkg/hasher/
+//
+//  The parallel one is about 5 times slower per core, so it is faster only with
+//  6 or more cores. See minWorkersParallel. Below is on a single core machine,
+//  so it only shows the cost per core. Note that "-2" does not run at once.
+//
+//    BenchmarkBlake3Parallel/sequential      21   54722942 ns/op   1226.34 MB/s
+//    BenchmarkBlake3Parallel/sequential-2    21   49380119 ns/op   1359.03 MB/s
+//    BenchmarkBlake3Parallel/parallel         4  305908328 ns/op    219.38 MB/s
+//    BenchmarkBlake3Parallel/parallel-2       5  261582005 ns/op    256.55 MB/s
+// ----------------------------------------------------------------------------
+
+func BenchmarkBlake3Parallel(b *testing.B) {
+	const sizeData = 64 * 1024 * 1024 // 64MiB
+
+	data := make([]byte, sizeData)
+	rand.New(rand.NewSource(3)).Read(data)
+
+	b.Run("sequential", func(b *testing.B) {
+		b.SetBytes(sizeData)
+
+		for i := 0; i < b.N; i++ {
+			_, err := _blake3(bytes.NewReader(data), 0)
+			require.NoError(b, err)
+		}
+	})
+
+	b.Run("parallel", func(b *testing.B) {
+		b.SetBytes(sizeData)
+
+		for i := 0; i < b.N; i++ {
+			_, err := _blake3Parallel(bytes.NewReader(data), 0, runtime.GOMAXPROCS(0))
+			require.NoError(b, err)
+		}
+	})
+}
@@ -0,0 +1,215 @@
+package hasher
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math/bits"
+
+	"github.com/pkg/errors"
+)
+
+// magicBlake3Resumable is the prefix of the saved state of blake3ResumableDigester.
+// The last byte is the version of the format.
+const magicBlake3Resumable = "b3r\x01"
+
+// ----------------------------------------------------------------------------
+//  Type: blake3ResumableDigester
+// ----------------------------------------------------------------------------
+
+// blake3ResumableDigester is the digester of BLAKE3 which state can be saved
+// and restored. The digest is the same as blake3Digester.
+//
+// Unlike blake3Digester, it uses the pure Go implementation of this package
+// since the state of github.com/zeebo/blake3 can not be accessed.
+type blake3ResumableDigester struct {
+	stripper *lineBreakStripper
+	state    *blake3State
+	lenOut   int
+}
+
+func newBlake3Resumable(lenOut int, context string) (*blake3ResumableDigester, error) {
+	// Validate the output length the same way as the sequential one
+	if _, err := newBlake3(lenOut, context); err != nil {
+		return nil, err
+	}
+
+	if lenOut == 0 {
+		lenOut = hashLenDefault
+	}
+
+	state := newBlake3State(newBlake3Mode(context))
+
+	return &blake3ResumableDigester{
+		stripper: &lineBreakStripper{w: state},
+		state:    state,
+		lenOut:   lenOut,
+	}, nil
+}
+
+// Write implements io.Writer. It never returns an error.
+func (d *blake3ResumableDigester) Write(p []byte) (int, error) {
+	return d.stripper.Write(p)
+}
+
+// Sum returns the digest of lenOut bytes of length. It never returns an error.
+func (d *blake3ResumableDigester) Sum() ([]byte, error) {
+	hashed := make([]byte, d.lenOut)
+
+	root := d.state.rootNode()
+	root.rootOutput(hashed)
+
+	return hashed, nil
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler. It never returns an error.
+//
+// The state is about 3 KiB at most. Which consists of the data of the current
+// chunk and the chaining values of the subtrees before it.
+func (d *blake3ResumableDigester) MarshalBinary() ([]byte, error) {
+	var buf bytes.Buffer
+
+	buf.WriteString(magicBlake3Resumable)
+
+	// Writing to bytes.Buffer never fails
+	_ = binary.Write(&buf, binary.BigEndian, d.stripper.isPendingCR)
+	_ = binary.Write(&buf, binary.BigEndian, d.state.counter)
+	_ = binary.Write(&buf, binary.BigEndian, uint16(len(d.state.chunk)))
+
+	buf.Write(d.state.chunk)
+
+	_ = binary.Write(&buf, binary.BigEndian, d.state.stack)
+
+	return buf.Bytes(), nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler. It restores the state
+// saved by MarshalBinary.
+func (d *blake3ResumableDigester) UnmarshalBinary(data []byte) error {
+	if !bytes.HasPrefix(data, []byte(magicBlake3Resumable)) {
+		return errors.New("invalid state of BLAKE3: unknown format")
+	}
+
+	var (
+		reader      = bytes.NewReader(data[len(magicBlake3Resumable):])
+		isPendingCR bool
+		counter     uint64
+		lenChunk    uint16
+	)
+
+	if err := readBinary(reader, &isPendingCR, &counter, &lenChunk); err != nil {
+		return errors.Wrap(err, "invalid state of BLAKE3")
+	}
+
+	// The number of the complete subtrees is the number of 1 bits of the counter.
+	numStack := bits.OnesCount64(counter)
+
+	if lenChunk > blake3LenChunk || (counter > 0 && lenChunk == 0) ||
+		reader.Len() != int(lenChunk)+numStack*len(blake3IV)*4 {
+		return errors.New("invalid state of BLAKE3: inconsistent length")
+	}
+
+	chunk := make([]byte, lenChunk, blake3LenChunk)
+	stack := make([][8]uint32, numStack)
+
+	if err := readBinary(reader, chunk, stack); err != nil {
+		return errors.Wrap(err, "invalid state of BLAKE3")
+	}
+
+	d.stripper.isPendingCR = isPendingCR
+	d.state.counter = counter
+	d.state.chunk = chunk
+	d.state.stack = stack
+
+	return nil
+}
+
+// ----------------------------------------------------------------------------
+//  Type: blake3State
+// ----------------------------------------------------------------------------
+
+// blake3State is an io.Writer which computes the BLAKE3 tree incrementally.
+type blake3State struct {
+	// mode is the key and the flags to hash with.
+	mode blake3Mode
+	// chunk is the data of the current chunk, up to 1 KiB.
+	chunk []byte
+	// stack is the chaining values of the complete subtrees before the current
+	// chunk. The larger subtree comes first.
+	stack [][8]uint32
+	// counter is the chunk counter of the current chunk.
+	counter uint64
+}
+
+func newBlake3State(mode blake3Mode) *blake3State {
+	return &blake3State{mode: mode}
+}
+
+// Write implements io.Writer. It never returns an error.
+func (s *blake3State) Write(p []byte) (int, error) {
+	lenP := len(p)
+
+	for len(p) > 0 {
+		// Finalize the full chunk only if more data follows. Otherwise it might
+		// be the root.
+		if len(s.chunk) == blake3LenChunk {
+			node := blake3ChunkNode(&s.mode.key, s.chunk, s.counter, s.mode.flags)
+
+			s.pushCV(node.chainingValue())
+			s.chunk = s.chunk[:0]
+		}
+
+		lenCopy := blake3LenChunk - len(s.chunk)
+		if lenCopy > len(p) {
+			lenCopy = len(p)
+		}
+
+		s.chunk = append(s.chunk, p[:lenCopy]...)
+		p = p[lenCopy:]
+	}
+
+	return lenP, nil
+}
+
+// It pushes the chaining value of the current chunk to the stack and merges
+// the subtrees of the same size.
+func (s *blake3State) pushCV(chainVal [8]uint32) {
+	s.counter++
+
+	// Each trailing 0 bit of the number of the chunks is a subtree to merge.
+	for total := s.counter; total&1 == 0; total >>= 1 {
+		left := s.stack[len(s.stack)-1]
+		s.stack = s.stack[:len(s.stack)-1]
+
+		node := blake3ParentNode(&s.mode.key, &left, &chainVal, s.mode.flags)
+		chainVal = node.chainingValue()
+	}
+
+	s.stack = append(s.stack, chainVal)
+}
+
+// It returns the root node of the data written so far.
+func (s *blake3State) rootNode() blake3Node {
+	node := blake3ChunkNode(&s.mode.key, s.chunk, s.counter, s.mode.flags)
+
+	for i := len(s.stack) - 1; i >= 0; i-- {
+		right := node.chainingValue()
+		node = blake3ParentNode(&s.mode.key, &s.stack[i], &right, s.mode.flags)
+	}
+
+	return node
+}
+
+// ----------------------------------------------------------------------------
+//  Functions
+// ----------------------------------------------------------------------------
+
+// It reads the big-endian values from reader in the given order.
+func readBinary(reader *bytes.Reader, values ...interface{}) error {
+	for _, value := range values {
+		if err := binary.Read(reader, binary.BigEndian, value); err != nil {
+			return errors.Wrap(err, "failed to read the state")
+		}
+	}
+
+	return nil
+}
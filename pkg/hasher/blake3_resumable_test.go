@@ -0,0 +1,110 @@
+package hasher
+
+import (
+	"bytes"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_blake3Resumable_same_as_sequential(t *testing.T) {
+	t.Parallel()
+
+	// Random data including line breaks to be stripped
+	data := make([]byte, 300*blake3LenChunk+7)
+	rand.New(rand.NewSource(1)).Read(data)
+
+	for _, size := range []int{
+		0, 1, blake3LenChunk - 1, blake3LenChunk, blake3LenChunk + 1,
+		2 * blake3LenChunk, 5*blake3LenChunk + 3, len(data),
+	} {
+		for _, lenOut := range []int{0, 8, 100} {
+			expect, err := _blake3(bytes.NewReader(data[:size]), lenOut)
+			require.NoError(t, err)
+
+			digest, err := newBlake3Resumable(lenOut, "")
+			require.NoError(t, err)
+
+			actual, err := stream(digest, bytes.NewReader(data[:size]))
+
+			require.NoError(t, err)
+			assert.Equal(t, expect, actual, "size: %d, lenOut: %d", size, lenOut)
+		}
+	}
+}
+
+func Test_blake3Resumable_marshal_and_resume(t *testing.T) {
+	t.Parallel()
+
+	data := []byte("line1\r\nline2\r\r\nline3\n")
+	data = append(data, bytes.Repeat([]byte("0123456789abcdef\r\n"), 500)...)
+
+	expect, err := _blake3(bytes.NewReader(data), 0)
+	require.NoError(t, err)
+
+	// Split at every point including between "\r" and "\n"
+	for split := 0; split <= len(data); split += 7 {
+		before, err := newBlake3Resumable(0, "")
+		require.NoError(t, err)
+
+		_, _ = before.Write(data[:split])
+
+		state, err := before.MarshalBinary()
+		require.NoError(t, err)
+
+		after, err := newBlake3Resumable(0, "")
+		require.NoError(t, err)
+		require.NoError(t, after.UnmarshalBinary(state), "split: %d", split)
+
+		_, _ = after.Write(data[split:])
+
+		actual, err := after.Sum()
+
+		require.NoError(t, err)
+		require.Equal(t, expect, actual, "split: %d", split)
+	}
+}
+
+func Test_blake3Resumable_invalid_lenOut(t *testing.T) {
+	t.Parallel()
+
+	digest, err := newBlake3Resumable(8195, "")
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid output length")
+	assert.Nil(t, digest)
+}
+
+func Test_blake3Resumable_UnmarshalBinary_error(t *testing.T) {
+	t.Parallel()
+
+	digest, err := newBlake3Resumable(0, "")
+	require.NoError(t, err)
+
+	_, _ = digest.Write(bytes.Repeat([]byte("a"), 3*blake3LenChunk+5))
+
+	state, err := digest.MarshalBinary()
+	require.NoError(t, err)
+
+	for _, test := range []struct {
+		expect string
+		state  []byte
+	}{
+		{expect: "unknown format", state: nil},
+		{expect: "unknown format", state: []byte("b3r\x02")},
+		{expect: "failed to read the state", state: state[:6]},
+		{expect: "inconsistent length", state: state[:len(state)-1]},
+		{expect: "inconsistent length", state: append(append([]byte{}, state...), 0)},
+	} {
+		restored, err := newBlake3Resumable(0, "")
+		require.NoError(t, err)
+
+		err = restored.UnmarshalBinary(test.state)
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid state of BLAKE3")
+		assert.Contains(t, err.Error(), test.expect)
+	}
+}
@@ -79,7 +79,7 @@ func Test_blake3_scan_error(t *testing.T) {
 	hashed, err := _blake3(d, 16)
 
 	require.Error(t, err)
-	assert.Contains(t, err.Error(), "failed to read during scanning")
+	assert.Contains(t, err.Error(), "failed to read input")
 	assert.Nil(t, hashed)
 }
 
@@ -0,0 +1,298 @@
+package hasher
+
+import (
+	"encoding/binary"
+	"math/bits"
+)
+
+// ============================================================================
+//  BLAKE3 Tree Primitives
+// ============================================================================
+//  This file implements the minimum of BLAKE3 to compute the chaining values of
+//  subtrees and the root output. It is used to hash the input in parallel, which
+//  the github.com/zeebo/blake3 package does not provide.
+//
+//  See the specification for details:
+//    https://github.com/BLAKE3-team/BLAKE3-specs/blob/master/blake3.pdf
+
+// ----------------------------------------------------------------------------
+//  Constants
+// ----------------------------------------------------------------------------
+
+const (
+	blake3LenBlock = 64   // byte length of a block
+	blake3LenChunk = 1024 // byte length of a chunk, 16 blocks
+
+	blake3FlagChunkStart        = uint32(1 << 0)
+	blake3FlagChunkEnd          = uint32(1 << 1)
+	blake3FlagParent            = uint32(1 << 2)
+	blake3FlagRoot              = uint32(1 << 3)
+	blake3FlagDeriveKeyContext  = uint32(1 << 5)
+	blake3FlagDeriveKeyMaterial = uint32(1 << 6)
+
+	blake3LenKey = 32 // byte length of a key
+)
+
+// blake3IV is the initialization vector of BLAKE3, same as SHA-256.
+//
+//nolint:gochecknoglobals // constant array
+var blake3IV = [8]uint32{
+	0x6A09E667, 0xBB67AE85, 0x3C6EF372, 0xA54FF53A,
+	0x510E527F, 0x9B05688C, 0x1F83D9AB, 0x5BE0CD19,
+}
+
+// blake3Schedule is the message word order of the 7 rounds. Which is the
+// permutation of the specification applied round by round.
+//
+//nolint:gochecknoglobals // constant array
+var blake3Schedule = [7][16]uint8{
+	{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15},
+	{2, 6, 3, 10, 7, 0, 4, 13, 1, 11, 12, 5, 9, 14, 15, 8},
+	{3, 4, 10, 12, 13, 2, 7, 14, 6, 5, 9, 0, 11, 15, 8, 1},
+	{10, 7, 12, 9, 14, 3, 13, 15, 4, 0, 11, 2, 5, 8, 1, 6},
+	{12, 13, 9, 11, 15, 10, 14, 8, 7, 2, 5, 3, 0, 1, 6, 4},
+	{9, 14, 11, 5, 8, 12, 15, 1, 13, 3, 0, 10, 2, 6, 4, 7},
+	{11, 15, 5, 0, 1, 9, 8, 6, 14, 10, 2, 12, 3, 4, 7, 13},
+}
+
+// ----------------------------------------------------------------------------
+//  Type: blake3Mode
+// ----------------------------------------------------------------------------
+
+// blake3Mode is the key and the flags to hash the input with. Which differs in
+// the hash mode and the derive-key mode.
+type blake3Mode struct {
+	key   [8]uint32
+	flags uint32
+}
+
+// It returns the blake3Mode of the context string. If the context is empty, it
+// is the hash mode. Otherwise it is the derive-key mode with the key derived
+// from the context.
+func newBlake3Mode(context string) blake3Mode {
+	if context == "" {
+		return blake3Mode{key: blake3IV}
+	}
+
+	// Hash the context in the context mode to derive the key
+	state := newBlake3State(blake3Mode{key: blake3IV, flags: blake3FlagDeriveKeyContext})
+	_, _ = state.Write([]byte(context))
+
+	var keyContext [blake3LenKey]byte
+
+	root := state.rootNode()
+	root.rootOutput(keyContext[:])
+
+	mode := blake3Mode{flags: blake3FlagDeriveKeyMaterial}
+
+	for i := range mode.key {
+		mode.key[i] = binary.LittleEndian.Uint32(keyContext[i*4:])
+	}
+
+	return mode
+}
+
+// ----------------------------------------------------------------------------
+//  Type: blake3Node
+// ----------------------------------------------------------------------------
+
+// blake3Node is the input of the last compression of a chunk or a parent node.
+// It becomes the chaining value of the node or, with the root flag, the root
+// output.
+type blake3Node struct {
+	cv       [8]uint32
+	block    [16]uint32
+	counter  uint64
+	blockLen uint32
+	flags    uint32
+}
+
+// chainingValue returns the chaining value of the non-root node.
+func (n *blake3Node) chainingValue() [8]uint32 {
+	state := blake3Compress(&n.cv, &n.block, n.counter, n.blockLen, n.flags)
+
+	var cv [8]uint32
+
+	copy(cv[:], state[:8])
+
+	return cv
+}
+
+// rootOutput fills out with the output of the root node. It is the extendable
+// output, so out can be any length.
+func (n *blake3Node) rootOutput(out []byte) {
+	var buf [blake3LenBlock]byte
+
+	for counter := uint64(0); len(out) > 0; counter++ {
+		state := blake3Compress(&n.cv, &n.block, counter, n.blockLen, n.flags|blake3FlagRoot)
+
+		for i, word := range state {
+			binary.LittleEndian.PutUint32(buf[i*4:], word)
+		}
+
+		out = out[copy(out, buf[:]):]
+	}
+}
+
+// ----------------------------------------------------------------------------
+//  Functions
+// ----------------------------------------------------------------------------
+
+// It returns the chaining value of the subtree of data. The data must be the
+// chunks starting from the chunk of the given counter. Except the last chunk,
+// the chunks must be full and, unless it is the whole input, the number of the
+// chunks must be a power of 2 or the subtree must be at the end of the input.
+func blake3SubtreeCV(key *[8]uint32, data []byte, counter uint64, flags uint32) [8]uint32 {
+	if len(data) <= blake3LenChunk {
+		node := blake3ChunkNode(key, data, counter, flags)
+
+		return node.chainingValue()
+	}
+
+	node := blake3SubtreeNode(key, data, counter, flags)
+
+	return node.chainingValue()
+}
+
+// It returns the node of the subtree of data, which is more than one chunk.
+func blake3SubtreeNode(key *[8]uint32, data []byte, counter uint64, flags uint32) blake3Node {
+	lenLeft := blake3LenLeft(len(data))
+
+	left := blake3SubtreeCV(key, data[:lenLeft], counter, flags)
+	right := blake3SubtreeCV(key, data[lenLeft:], counter+uint64(lenLeft/blake3LenChunk), flags)
+
+	return blake3ParentNode(key, &left, &right, flags)
+}
+
+// It returns the parent node of the chaining values of the consecutive subtrees.
+// There must be 2 or more. Except the last one, the subtrees must be of the
+// same power of 2 number of chunks.
+func blake3MergeNode(key *[8]uint32, cvs [][8]uint32, flags uint32) blake3Node {
+	lenLeft := 1 << (bits.Len(uint(len(cvs)-1)) - 1) // largest power of 2 below
+
+	left := blake3MergeCV(key, cvs[:lenLeft], flags)
+	right := blake3MergeCV(key, cvs[lenLeft:], flags)
+
+	return blake3ParentNode(key, &left, &right, flags)
+}
+
+// It returns the chaining value of the merged subtrees. See blake3MergeNode.
+func blake3MergeCV(key *[8]uint32, cvs [][8]uint32, flags uint32) [8]uint32 {
+	if len(cvs) == 1 {
+		return cvs[0]
+	}
+
+	node := blake3MergeNode(key, cvs, flags)
+
+	return node.chainingValue()
+}
+
+// It returns the byte length of the left subtree. Which is the largest power
+// of 2 number of chunks that leaves at least 1 byte to the right subtree.
+func blake3LenLeft(lenData int) int {
+	numChunks := uint64(lenData-1) / blake3LenChunk // full chunks on the left
+
+	return (1 << (bits.Len64(numChunks) - 1)) * blake3LenChunk
+}
+
+// It returns the last node of the chunk. The chunk must be 1 KiB or less.
+func blake3ChunkNode(key *[8]uint32, chunk []byte, counter uint64, flags uint32) blake3Node {
+	chainVal := *key
+	flagStart := blake3FlagChunkStart
+
+	// Compress all the blocks except the last one
+	for len(chunk) > blake3LenBlock {
+		var block [16]uint32
+
+		blake3BlockWords(&block, chunk[:blake3LenBlock])
+
+		state := blake3Compress(&chainVal, &block, counter, blake3LenBlock, flags|flagStart)
+		copy(chainVal[:], state[:8])
+
+		chunk = chunk[blake3LenBlock:]
+		flagStart = 0
+	}
+
+	node := blake3Node{
+		cv:       chainVal,
+		counter:  counter,
+		blockLen: uint32(len(chunk)),
+		flags:    flags | flagStart | blake3FlagChunkEnd,
+	}
+
+	blake3BlockWords(&node.block, chunk)
+
+	return node
+}
+
+// It returns the parent node of the two chaining values.
+func blake3ParentNode(key, left, right *[8]uint32, flags uint32) blake3Node {
+	node := blake3Node{
+		cv:       *key,
+		blockLen: blake3LenBlock,
+		flags:    flags | blake3FlagParent,
+	}
+
+	copy(node.block[:8], left[:])
+	copy(node.block[8:], right[:])
+
+	return node
+}
+
+// It sets the block, up to 64 bytes, as little-endian words padded with zeros.
+func blake3BlockWords(words *[16]uint32, block []byte) {
+	var buf [blake3LenBlock]byte
+
+	copy(buf[:], block)
+
+	for i := range words {
+		words[i] = binary.LittleEndian.Uint32(buf[i*4:])
+	}
+}
+
+// It is the compression function of BLAKE3.
+func blake3Compress(cv *[8]uint32, block *[16]uint32, counter uint64, blockLen, flags uint32) [16]uint32 {
+	v0, v1, v2, v3 := cv[0], cv[1], cv[2], cv[3]
+	v4, v5, v6, v7 := cv[4], cv[5], cv[6], cv[7]
+	v8, v9, v10, v11 := blake3IV[0], blake3IV[1], blake3IV[2], blake3IV[3]
+	v12, v13, v14, v15 := uint32(counter), uint32(counter>>32), blockLen, flags
+
+	for round := range blake3Schedule {
+		msg := &blake3Schedule[round]
+
+		// Mix the columns
+		v0, v4, v8, v12 = blake3G(v0, v4, v8, v12, block[msg[0]], block[msg[1]])
+		v1, v5, v9, v13 = blake3G(v1, v5, v9, v13, block[msg[2]], block[msg[3]])
+		v2, v6, v10, v14 = blake3G(v2, v6, v10, v14, block[msg[4]], block[msg[5]])
+		v3, v7, v11, v15 = blake3G(v3, v7, v11, v15, block[msg[6]], block[msg[7]])
+
+		// Mix the diagonals
+		v0, v5, v10, v15 = blake3G(v0, v5, v10, v15, block[msg[8]], block[msg[9]])
+		v1, v6, v11, v12 = blake3G(v1, v6, v11, v12, block[msg[10]], block[msg[11]])
+		v2, v7, v8, v13 = blake3G(v2, v7, v8, v13, block[msg[12]], block[msg[13]])
+		v3, v4, v9, v14 = blake3G(v3, v4, v9, v14, block[msg[14]], block[msg[15]])
+	}
+
+	return [16]uint32{
+		v0 ^ v8, v1 ^ v9, v2 ^ v10, v3 ^ v11,
+		v4 ^ v12, v5 ^ v13, v6 ^ v14, v7 ^ v15,
+		v8 ^ cv[0], v9 ^ cv[1], v10 ^ cv[2], v11 ^ cv[3],
+		v12 ^ cv[4], v13 ^ cv[5], v14 ^ cv[6], v15 ^ cv[7],
+	}
+}
+
+// It is the quarter-round mixing function of BLAKE3.
+//
+//nolint:varnamelen // names from the specification
+func blake3G(a, b, c, d, mx, my uint32) (uint32, uint32, uint32, uint32) {
+	a += b + mx
+	d = bits.RotateLeft32(d^a, -16)
+	c += d
+	b = bits.RotateLeft32(b^c, -12)
+	a += b + my
+	d = bits.RotateLeft32(d^a, -8)
+	c += d
+	b = bits.RotateLeft32(b^c, -7)
+
+	return a, b, c, d
+}
@@ -15,7 +15,7 @@ func Test_crc32_golen(t *testing.T) {
 	input := "Hello world!"
 	r := strings.NewReader(input)
 
-	chksum, err := _crc32(r)
+	chksum, err := _crc32(r, CRC32Poly)
 
 	require.NoError(t, err)
 	assert.Equal(t, 4, len(chksum))
@@ -32,9 +32,9 @@ func Test_crc32_fail_copy(t *testing.T) {
 
 	// See hasher_test.go for dummyReader struct
 	d := dummyReader{}
-	checksum, err := _crc32(d)
+	checksum, err := _crc32(d, CRC32Poly)
 
 	require.Error(t, err)
-	assert.Contains(t, err.Error(), "failed to copy data to hasher")
+	assert.Contains(t, err.Error(), "failed to read input")
 	assert.Nil(t, checksum)
 }
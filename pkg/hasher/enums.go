@@ -25,6 +25,10 @@ const (
 	// HashAlgoSHA3_512 is the enum of SHA3-512 hash algorithm. Set this to
 	// "hasher.HashAlgo" to use this algorithm.
 	HashAlgoSHA3_512
+	// HashAlgoXXH64 is the enum of 64 bit xxHash (XXH64) algorithm. It is not a
+	// cryptographic hash but a fast one, which digest is up to 8 bytes. This is
+	// the algorithm used in the fast mode of genrawid.
+	HashAlgoXXH64
 )
 
 const (
@@ -35,6 +39,13 @@ const (
 	ChkSumCRC32
 	// ChkSumXXHash is the enum of xxHash algorithm as a checksum.
 	ChkSumXXHash
+	// ChkSumXOR8 is the enum of the 8 bit/1 Byte longitudinal redundancy check
+	// (LRC). See the _xor8 function for its error-detection properties.
+	ChkSumXOR8
+	// ChkSumXOR16 is the enum of the 16 bit/2 Byte XOR checksum. This is the
+	// checksum used in the fast mode of genrawid. See the _xor16 function for
+	// its error-detection properties.
+	ChkSumXOR16
 )
 
 // ----------------------------------------------------------------------------
@@ -49,6 +60,8 @@ func (h THashAlgo) String() string {
 		return "blake3"
 	case HashAlgoSHA3_512:
 		return "sha3-512"
+	case HashAlgoXXH64:
+		return "xxh64"
 	case HashAlgoUnknown:
 		fallthrough
 	default:
@@ -64,9 +77,61 @@ func (h TChkSumAlgo) String() string {
 		return "crc32"
 	case ChkSumXXHash:
 		return "xxhash"
+	case ChkSumXOR8:
+		return "xor8"
+	case ChkSumXOR16:
+		return "xor16"
 	case ChkSumUnknown:
 		fallthrough
 	default:
 		return "unknown"
 	}
 }
+
+// ----------------------------------------------------------------------------
+//  Functions
+// ----------------------------------------------------------------------------
+
+// HashAlgos returns the available hash algorithms. The first one is the default.
+func HashAlgos() []THashAlgo {
+	return []THashAlgo{
+		HashAlgoBLAKE3,
+		HashAlgoSHA3_512,
+		HashAlgoXXH64,
+	}
+}
+
+// ChkSumAlgos returns the available checksum algorithms in descending order of
+// their error-detection properties. The first one is the default.
+func ChkSumAlgos() []TChkSumAlgo {
+	return []TChkSumAlgo{
+		ChkSumCRC32,
+		ChkSumXXHash,
+		ChkSumXOR16,
+		ChkSumXOR8,
+	}
+}
+
+// ParseHashAlgo returns the THashAlgo of the given name. The name is the same as
+// the one returned by THashAlgo.String(), such as "blake3" or "sha3-512".
+func ParseHashAlgo(name string) (THashAlgo, error) {
+	for _, algo := range HashAlgos() {
+		if algo.String() == name {
+			return algo, nil
+		}
+	}
+
+	return HashAlgoUnknown, newUnknownAlgoError("hash", name)
+}
+
+// ParseChkSumAlgo returns the TChkSumAlgo of the given name. The name is the
+// same as the one returned by TChkSumAlgo.String(), such as "crc32" or "xor16".
+func ParseChkSumAlgo(name string) (TChkSumAlgo, error) {
+	for _, algo := range ChkSumAlgos() {
+		if algo.String() == name {
+			return algo, nil
+		}
+	}
+
+	return ChkSumUnknown, newUnknownAlgoError("checksum", name)
+}
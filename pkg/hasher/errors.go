@@ -0,0 +1,108 @@
+package hasher
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+)
+
+// ----------------------------------------------------------------------------
+//  Errors
+// ----------------------------------------------------------------------------
+
+var (
+	// ErrUnknownAlgorithm is the error returned if the hash or checksum algorithm
+	// is unknown or not supported. Use errors.Is to check it.
+	ErrUnknownAlgorithm = errors.New("unknown algorithm")
+
+	// ErrRead is the error returned if the input could not be read. The error of
+	// the reader is kept as well, so both can be checked with errors.Is.
+	ErrRead = errors.New("failed to read input")
+)
+
+// ErrInvalidLength is the error returned if the output length is out of the
+// range of the algorithm. Use errors.As to get the details.
+//
+//	var errLen *hasher.ErrInvalidLength
+//	if errors.As(err, &errLen) {
+//	    fmt.Println(errLen.Min, errLen.Max)
+//	}
+type ErrInvalidLength struct {
+	// Given is the given length in bytes.
+	Given int
+	// Min is the minimum length in bytes.
+	Min int
+	// Max is the maximum length in bytes. It is 0 if there is no upper limit.
+	Max int
+}
+
+// Error implements the error interface.
+func (e *ErrInvalidLength) Error() string {
+	if e.Max == 0 {
+		return fmt.Sprintf("invalid output length. It must be %d or more. Given length: %d",
+			e.Min, e.Given)
+	}
+
+	return fmt.Sprintf("invalid output length. It must be between %d and %d. Given length: %d",
+		e.Min, e.Max, e.Given)
+}
+
+// ----------------------------------------------------------------------------
+//  Type: unknownAlgoError
+// ----------------------------------------------------------------------------
+
+// unknownAlgoError is ErrUnknownAlgorithm with the kind and the name of the
+// algorithm in the message. Such as "unknown hash algorithm: md5".
+type unknownAlgoError struct {
+	kind string
+	name string
+}
+
+func newUnknownAlgoError(kind, name string) error {
+	return &unknownAlgoError{kind: kind, name: name}
+}
+
+// Error implements the error interface.
+func (e *unknownAlgoError) Error() string {
+	return fmt.Sprintf("unknown %s algorithm: %s", e.kind, e.name)
+}
+
+// Is reports whether target is ErrUnknownAlgorithm. It is used by errors.Is.
+func (e *unknownAlgoError) Is(target error) bool {
+	return target == ErrUnknownAlgorithm //nolint:errorlint // comparing the sentinel itself
+}
+
+// ----------------------------------------------------------------------------
+//  Type: readError
+// ----------------------------------------------------------------------------
+
+// readError is ErrRead that keeps the error of the reader.
+type readError struct {
+	cause error
+}
+
+// NewErrRead returns ErrRead that wraps the given error of the reader. It is
+// for the callers that read the input by themselves, such as opening a file,
+// to report the failure the same way as this package.
+func NewErrRead(cause error) error {
+	if cause == nil {
+		return nil
+	}
+
+	return &readError{cause: cause}
+}
+
+// Error implements the error interface.
+func (e *readError) Error() string {
+	return ErrRead.Error() + ": " + e.cause.Error()
+}
+
+// Unwrap returns the error of the reader.
+func (e *readError) Unwrap() error {
+	return e.cause
+}
+
+// Is reports whether target is ErrRead. It is used by errors.Is.
+func (e *readError) Is(target error) bool {
+	return target == ErrRead //nolint:errorlint // comparing the sentinel itself
+}
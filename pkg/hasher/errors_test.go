@@ -0,0 +1,99 @@
+package hasher
+
+import (
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestErrUnknownAlgorithm(t *testing.T) {
+	t.Parallel()
+
+	_, errParseHash := ParseHashAlgo("md5")
+	_, errParseChkSum := ParseChkSumAlgo("md5")
+	_, errHash := Config{HashAlgo: HashAlgoUnknown}.Hash(strings.NewReader("foo"))
+	_, errChkSum := Config{ChkSumAlgo: ChkSumUnknown}.CheckSum(strings.NewReader("foo"))
+	_, errResumable := Config{HashAlgo: HashAlgoUnknown}.NewResumableDigester()
+
+	for _, test := range []struct {
+		err    error
+		expect string
+	}{
+		{errParseHash, "unknown hash algorithm: md5"},
+		{errParseChkSum, "unknown checksum algorithm: md5"},
+		{errHash, "unknown hash algorithm: unknown"},
+		{errChkSum, "unknown checksum algorithm: unknown"},
+		{errResumable, "unknown hash algorithm: unknown"},
+	} {
+		require.Error(t, test.err)
+		assert.Equal(t, test.expect, test.err.Error())
+		assert.True(t, errors.Is(test.err, ErrUnknownAlgorithm), "it should be ErrUnknownAlgorithm: %v", test.err)
+	}
+}
+
+func TestErrInvalidLength(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		conf   Config
+		expect ErrInvalidLength
+	}{
+		{Config{HashAlgo: HashAlgoBLAKE3, HashLen: 8195}, ErrInvalidLength{Given: 8195, Min: 1, Max: 8194}},
+		{Config{HashAlgo: HashAlgoBLAKE3, HashLen: -1, Concurrency: 2}, ErrInvalidLength{Given: -1, Min: 1, Max: 8194}},
+		{Config{HashAlgo: HashAlgoSHA3_512, HashLen: 65}, ErrInvalidLength{Given: 65, Min: 1, Max: 64}},
+		{Config{HashAlgo: HashAlgoXXH64, HashLen: 9}, ErrInvalidLength{Given: 9, Min: 1, Max: 8}},
+	} {
+		_, err := test.conf.Hash(strings.NewReader("foo"))
+
+		var errLen *ErrInvalidLength
+
+		require.True(t, errors.As(err, &errLen), "it should be ErrInvalidLength: %v", err)
+		assert.Equal(t, test.expect, *errLen)
+		assert.Contains(t, err.Error(), "invalid output length")
+	}
+}
+
+func TestErrInvalidLength_Error_no_max(t *testing.T) {
+	t.Parallel()
+
+	err := &ErrInvalidLength{Given: 2, Min: 4}
+
+	assert.Equal(t, "invalid output length. It must be 4 or more. Given length: 2", err.Error())
+}
+
+func TestErrRead(t *testing.T) {
+	t.Parallel()
+
+	errForced := errors.New("forced error")
+
+	for _, conf := range []Config{
+		{HashAlgo: HashAlgoBLAKE3},
+		{HashAlgo: HashAlgoBLAKE3, Concurrency: 2},
+		{HashAlgo: HashAlgoSHA3_512},
+		{HashAlgo: HashAlgoXXH64},
+	} {
+		_, err := conf.Hash(iotest.ErrReader(errForced))
+
+		require.Error(t, err)
+		assert.Equal(t, "failed to read input: forced error", err.Error())
+		assert.True(t, errors.Is(err, ErrRead), "it should be ErrRead")
+		assert.True(t, errors.Is(err, errForced), "it should keep the error of the reader")
+		assert.False(t, errors.Is(err, ErrUnknownAlgorithm))
+	}
+}
+
+func TestNewErrRead(t *testing.T) {
+	t.Parallel()
+
+	require.NoError(t, NewErrRead(nil), "it should be nil if no error given")
+
+	err := NewErrRead(io.ErrUnexpectedEOF)
+
+	assert.True(t, errors.Is(err, ErrRead))
+	assert.True(t, errors.Is(err, io.ErrUnexpectedEOF))
+}
@@ -60,6 +60,30 @@ func ExampleCheckSum_xxhash() {
 	// Checksum value    : a9d4d413
 }
 
+func ExampleConfig_CheckSum() {
+	// Config computes the checksum without changing the package-level variables.
+	conf := hasher.NewConfig()
+	conf.ChkSumAlgo = hasher.ChkSumXOR16
+
+	input := "1234567890"
+
+	sumByte, err := conf.CheckSum(strings.NewReader(input))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	fmt.Printf("Checksum algorithm: %v\n", conf.ChkSumAlgo)
+	fmt.Printf("Length of checksum: %v bytes\n", len(sumByte))
+	fmt.Printf("Checksum value    : %x\n", sumByte)
+	fmt.Printf("Package default   : %v\n", hasher.ChkSumAlgo)
+
+	// Output:
+	// Checksum algorithm: xor16
+	// Length of checksum: 2 bytes
+	// Checksum value    : 3839
+	// Package default   : crc32
+}
+
 func ExampleHash() {
 	input := "This is a string"
 	r := strings.NewReader(input)
@@ -115,6 +139,8 @@ func ExampleTChkSumAlgo_String() {
 		hasher.ChkSumUnknown,
 		hasher.ChkSumCRC32,
 		hasher.ChkSumXXHash,
+		hasher.ChkSumXOR8,
+		hasher.ChkSumXOR16,
 	} {
 		fmt.Printf("Value: %#v, String: %s\n", chkSumAlgo, chkSumAlgo)
 	}
@@ -123,6 +149,8 @@ func ExampleTChkSumAlgo_String() {
 	// Value: 0, String: unknown
 	// Value: 1, String: crc32
 	// Value: 2, String: xxhash
+	// Value: 3, String: xor8
+	// Value: 4, String: xor16
 }
 
 func ExampleTHashAlgo_String() {
@@ -130,6 +158,7 @@ func ExampleTHashAlgo_String() {
 		hasher.HashAlgoUnknown,
 		hasher.HashAlgoBLAKE3,
 		hasher.HashAlgoSHA3_512,
+		hasher.HashAlgoXXH64,
 	} {
 		fmt.Printf("Value: %#v, String: %s\n", chkSumAlgo, chkSumAlgo)
 	}
@@ -138,4 +167,5 @@ func ExampleTHashAlgo_String() {
 	// Value: 0, String: unknown
 	// Value: 1, String: blake3
 	// Value: 2, String: sha3-512
+	// Value: 3, String: xxh64
 }
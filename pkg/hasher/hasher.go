@@ -6,6 +6,7 @@ package hasher
 import (
 	"hash/crc32"
 	"io"
+	"runtime"
 
 	"github.com/KEINOS/go-genrawid/pkg/rawid"
 	"github.com/pkg/errors"
@@ -25,6 +26,16 @@ const chksumAlgoDefault = ChkSumCRC32
 // errors than IEEE polynomial.
 const crc32PolyDefault = uint32(crc32.Castagnoli)
 
+// ConcurrencyDefault is the default number of goroutines to compute the hash.
+// Currently it is 1, which computes sequentially.
+const concurrencyDefault = 1
+
+// minWorkersParallel is the least number of goroutines running at once for the
+// parallel BLAKE3 to be faster than the sequential one. The parallel one is a
+// pure Go implementation, which is about 5 times slower per core than the one
+// with the SIMD instructions. See BenchmarkBlake3Parallel.
+const minWorkersParallel = 6
+
 // HashAlgoTypeDefault is the default hash algorithm. Currently it is BLAKE3.
 const hashAlgoDefault = HashAlgoBLAKE3
 
@@ -39,9 +50,33 @@ const hashLenDefault = 64
 // ChkSumAlgo is the checksum algorithm to use.
 //
 // One of TChkSumAlgo type must be set. By default it is ChkSumCRC32(=CRC32).
-// Currently, we only support CRC32.
+//
+// The error-detection properties of the available algorithms in descending
+// order are: CRC32 (with Castagnoli polynomial), xxHash, XOR16 and XOR8.
 var ChkSumAlgo = chksumAlgoDefault
 
+// Concurrency is the number of goroutines to compute the BLAKE3 hash of large
+// inputs. By default it is 1, which computes sequentially. A negative value uses
+// the number of CPUs.
+//
+// The digest is the same regardless of this value. Note that the parallel one
+// is a pure Go implementation, while the sequential one uses the SIMD
+// instructions if available. So it is faster only with enough cores, and the
+// sequential one is used unless 6 or more goroutines can run at once.
+var Concurrency = concurrencyDefault
+
+// Context is the context string to use the derive-key mode of BLAKE3. By default
+// it is empty and the regular hash mode is used.
+//
+// The digests of the same input differ for each context. Which is useful to
+// separate the rawids of different kinds of objects. The context should be
+// hardcoded, globally unique and application-specific. Such as:
+//
+//	"acme 2026-10-19 manifest v1"
+//
+// Setting it to the algorithm other than BLAKE3 is an error.
+var Context = ""
+
 // CRC32Poly is the polynomial used in the CRC32 algorithm.
 //
 // By default it uses Castagnoli polynomial. Overwrite this value to use a
@@ -67,42 +102,173 @@ var HashLen = hashLenDefault
 
 // Hash returns the hash/digest of input. By default the returned digest length
 // is 64 bytes.
+//
+// It uses the HashAlgo and HashLen variables. Use Config.Hash to compute with
+// a different setting without changing them.
 func Hash(input io.Reader) (rawid.ID, error) {
+	return NewConfig().Hash(input)
+}
+
+// CheckSum returns the checksum of input. By default it is the CRC-32 checksum.
+//
+// The CRC32Poly variable is used as a polynomial to create the table. By default
+// it uses Castagnoli polynomial. A.k.a. CRC32C or CRC32-Castagnoli.
+//
+// It uses the ChkSumAlgo and CRC32Poly variables. Use Config.CheckSum to compute
+// with a different setting without changing them.
+func CheckSum(input io.Reader) (rawid.ID, error) {
+	return NewConfig().CheckSum(input)
+}
+
+// ----------------------------------------------------------------------------
+//  Type: Config
+// ----------------------------------------------------------------------------
+
+// Config holds the algorithms and their parameters to compute the hash and the
+// checksum. It is useful to use more than one setting at the same time without
+// changing the package-level variables.
+type Config struct {
+	// HashAlgo is the hash algorithm to use.
+	HashAlgo THashAlgo
+	// HashLen is the hash digest length in bytes.
+	HashLen int
+	// ChkSumAlgo is the checksum algorithm to use.
+	ChkSumAlgo TChkSumAlgo
+	// CRC32Poly is the polynomial used if ChkSumAlgo is ChkSumCRC32.
+	CRC32Poly uint32
+	// Concurrency is the number of goroutines to compute the BLAKE3 hash of
+	// large inputs. 0 or 1 computes sequentially and a negative value uses the
+	// number of CPUs. See the Concurrency variable.
+	Concurrency int
+	// Context is the context string of the derive-key mode of BLAKE3. If empty,
+	// the hash mode is used. See the Context variable.
+	Context string
+}
+
+// NewConfig returns a Config set with the current values of the HashAlgo,
+// HashLen, ChkSumAlgo, CRC32Poly, Concurrency and Context variables.
+func NewConfig() Config {
+	return Config{
+		HashAlgo:    HashAlgo,
+		HashLen:     HashLen,
+		ChkSumAlgo:  ChkSumAlgo,
+		CRC32Poly:   CRC32Poly,
+		Concurrency: Concurrency,
+		Context:     Context,
+	}
+}
+
+// Hash returns the hash/digest of input using the HashAlgo and HashLen of the
+// Config.
+//
+// Note that BLAKE3 and SHA3-512 ignore the line breaks ("\n" and "\r\n") of
+// the input. This is to keep the digests the same as the former versions which
+// read the input line by line.
+func (c Config) Hash(input io.Reader) (rawid.ID, error) {
 	if input == nil {
 		return nil, errors.New("nil pointer for input given")
 	}
 
-	switch HashAlgo {
+	digest, err := c.NewDigester()
+	if err != nil {
+		return nil, err
+	}
+
+	return stream(digest, input)
+}
+
+// NewDigester returns a Digester of the HashAlgo and HashLen of the Config.
+// The Sum of the Digester is the same as Hash of the data written.
+//
+// It is useful to compute the hashes of more than one Config in a single pass,
+// such as writing the input via io.MultiWriter.
+func (c Config) NewDigester() (Digester, error) {
+	if err := c.checkContext(); err != nil {
+		return nil, err
+	}
+
+	var (
+		digest Digester
+		err    error
+	)
+
+	switch c.HashAlgo {
 	case HashAlgoBLAKE3:
-		return _blake3(input, HashLen)
+		if numWorkers := c.numWorkers(); isParallelFaster(numWorkers) {
+			digest, err = newBlake3Parallel(c.HashLen, numWorkers, c.Context)
+		} else {
+			digest, err = newBlake3(c.HashLen, c.Context)
+		}
 	case HashAlgoSHA3_512:
-		return _sha3_512(input, HashLen)
+		digest, err = newSHA3512(c.HashLen)
+	case HashAlgoXXH64:
+		digest, err = newXXHash(c.HashLen)
 	case HashAlgoUnknown:
 		fallthrough
 	default:
-		return nil, errors.Errorf("unknown hash algorithm: %s", HashAlgo)
+		err = newUnknownAlgoError("hash", c.HashAlgo.String())
+	}
+
+	if err != nil {
+		return nil, err
 	}
+
+	return digest, nil
 }
 
-// CheckSum returns the CRC-32 checksum of input.
+// It returns an error if the Context is set to the algorithm other than BLAKE3.
+// Ignoring it silently would mix the rawids of different contexts.
+func (c Config) checkContext() error {
+	if c.Context != "" && c.HashAlgo != HashAlgoBLAKE3 {
+		return errors.Errorf("context is supported only by BLAKE3. hash algorithm: %s", c.HashAlgo)
+	}
+
+	return nil
+}
+
+// It returns the number of goroutines to compute the hash. See Config.Concurrency.
+func (c Config) numWorkers() int {
+	if c.Concurrency < 0 {
+		return runtime.NumCPU()
+	}
+
+	return c.Concurrency
+}
+
+// It returns true if the parallel BLAKE3 with numWorkers goroutines is faster
+// than the sequential one. The goroutines more than GOMAXPROCS do not run at
+// once.
+func isParallelFaster(numWorkers int) bool {
+	if maxProcs := runtime.GOMAXPROCS(0); numWorkers > maxProcs {
+		numWorkers = maxProcs
+	}
+
+	return numWorkers >= minWorkersParallel
+}
+
+// CheckSum returns the checksum of input using the ChkSumAlgo of the Config.
 //
-// The CRC32Poly variable is used as a polynomial to create the table. By default
-// it uses Castagnoli polynomial. A.k.a. CRC32C or CRC32-Castagnoli.
-func CheckSum(input io.Reader) (rawid.ID, error) {
-	const lenByte = 4 // output length
+// The length of the checksum depends on the algorithm. It is 4 bytes for CRC32
+// and xxHash, 2 bytes for XOR16 and 1 byte for XOR8.
+func (c Config) CheckSum(input io.Reader) (rawid.ID, error) {
+	const lenByte = 4 // output length of xxHash
 
 	if input == nil {
 		return nil, errors.New("nil pointer for input given")
 	}
 
-	switch ChkSumAlgo {
+	switch c.ChkSumAlgo {
 	case ChkSumCRC32:
-		return _crc32(input)
+		return _crc32(input, c.CRC32Poly)
 	case ChkSumXXHash:
 		return _xxhash(input, lenByte)
+	case ChkSumXOR8:
+		return _xor8(input)
+	case ChkSumXOR16:
+		return _xor16(input)
 	case ChkSumUnknown:
 		fallthrough
 	default:
-		return nil, errors.Errorf("unknown checksum algorithm: %s", ChkSumAlgo)
+		return nil, newUnknownAlgoError("checksum", c.ChkSumAlgo.String())
 	}
 }
@@ -1,6 +1,7 @@
 package hasher
 
 import (
+	"bytes"
 	"fmt"
 	"io"
 	"log"
@@ -10,6 +11,7 @@ import (
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/zeebo/blake3"
 )
 
 // ----------------------------------------------------------------------------
@@ -52,6 +54,32 @@ func TestCheckSum_nil_input(t *testing.T) {
 	assert.Nil(t, checksum, "returned checksum should be nil on error")
 }
 
+func TestConfig_CheckSum_golden(t *testing.T) {
+	t.Parallel()
+
+	input := "1234567890"
+
+	for _, test := range []struct {
+		algo   TChkSumAlgo
+		expect string
+	}{
+		{ChkSumCRC32, "f3dbd4fe"},
+		{ChkSumXXHash, "a9d4d413"},
+		{ChkSumXOR8, "f3"},
+		{ChkSumXOR16, "3839"},
+	} {
+		conf := NewConfig()
+		conf.ChkSumAlgo = test.algo
+
+		sumByte, err := conf.CheckSum(strings.NewReader(input))
+		require.NoError(t, err)
+
+		expect := test.expect
+		actual := fmt.Sprintf("%x", sumByte)
+		assert.Equal(t, expect, actual, "algorithm: %s", test.algo)
+	}
+}
+
 //nolint:paralleltest // do not parallelize due to dependency on other tests
 func TestCheckSum_unknown_algo(t *testing.T) {
 	oldChkSumAlgo := ChkSumAlgo
@@ -69,6 +97,70 @@ func TestCheckSum_unknown_algo(t *testing.T) {
 	assert.Nil(t, checksum, "returned checksum should be nil on error")
 }
 
+// ----------------------------------------------------------------------------
+//  HashAlgos and ChkSumAlgos
+// ----------------------------------------------------------------------------
+
+func TestHashAlgos_default_first(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, hashAlgoDefault, HashAlgos()[0], "the first one should be the default")
+	assert.Equal(t, chksumAlgoDefault, ChkSumAlgos()[0], "the first one should be the default")
+	assert.NotContains(t, HashAlgos(), HashAlgoUnknown)
+	assert.NotContains(t, ChkSumAlgos(), ChkSumUnknown)
+}
+
+// ----------------------------------------------------------------------------
+//  ParseChkSumAlgo
+// ----------------------------------------------------------------------------
+
+func TestParseChkSumAlgo(t *testing.T) {
+	t.Parallel()
+
+	for _, expect := range []TChkSumAlgo{
+		ChkSumCRC32,
+		ChkSumXXHash,
+		ChkSumXOR8,
+		ChkSumXOR16,
+	} {
+		actual, err := ParseChkSumAlgo(expect.String())
+
+		require.NoError(t, err)
+		assert.Equal(t, expect, actual)
+	}
+
+	algo, err := ParseChkSumAlgo("unknown")
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unknown checksum algorithm: unknown")
+	assert.Equal(t, ChkSumUnknown, algo)
+}
+
+// ----------------------------------------------------------------------------
+//  ParseHashAlgo
+// ----------------------------------------------------------------------------
+
+func TestParseHashAlgo(t *testing.T) {
+	t.Parallel()
+
+	for _, expect := range []THashAlgo{
+		HashAlgoBLAKE3,
+		HashAlgoSHA3_512,
+		HashAlgoXXH64,
+	} {
+		actual, err := ParseHashAlgo(expect.String())
+
+		require.NoError(t, err)
+		assert.Equal(t, expect, actual)
+	}
+
+	algo, err := ParseHashAlgo("unknown")
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unknown hash algorithm: unknown")
+	assert.Equal(t, HashAlgoUnknown, algo)
+}
+
 // ----------------------------------------------------------------------------
 //  Hash
 // ----------------------------------------------------------------------------
@@ -114,6 +206,139 @@ func TestHash_unknown_algo(t *testing.T) {
 	assert.Nil(t, digest, "returned digest should be nil on error")
 }
 
+// ----------------------------------------------------------------------------
+//  Config.NewDigester
+// ----------------------------------------------------------------------------
+
+func TestConfig_NewDigester_same_as_Hash(t *testing.T) {
+	t.Parallel()
+
+	const input = "abcd\r\nefgh\n"
+
+	confs := []Config{
+		{HashAlgo: HashAlgoBLAKE3},
+		{HashAlgo: HashAlgoBLAKE3, HashLen: 32, Concurrency: 2},
+		{HashAlgo: HashAlgoSHA3_512},
+		{HashAlgo: HashAlgoXXH64},
+	}
+
+	// Compute all the hashes in a single pass
+	digesters := make([]Digester, len(confs))
+	writers := make([]io.Writer, len(confs))
+
+	for i, conf := range confs {
+		digest, err := conf.NewDigester()
+		require.NoError(t, err)
+
+		digesters[i], writers[i] = digest, digest
+	}
+
+	_, err := io.Copy(io.MultiWriter(writers...), strings.NewReader(input))
+	require.NoError(t, err)
+
+	for i, conf := range confs {
+		expect, err := conf.Hash(strings.NewReader(input))
+		require.NoError(t, err)
+
+		actual, err := digesters[i].Sum()
+		require.NoError(t, err)
+
+		assert.Equal(t, []byte(expect), actual, "algorithm: %s", conf.HashAlgo)
+	}
+}
+
+func TestConfig_NewDigester_error(t *testing.T) {
+	t.Parallel()
+
+	for _, conf := range []Config{
+		{HashAlgo: HashAlgoUnknown},
+		{HashAlgo: HashAlgoBLAKE3, HashLen: -1},
+		{HashAlgo: HashAlgoBLAKE3, HashLen: -1, Concurrency: 2},
+		{HashAlgo: HashAlgoSHA3_512, HashLen: 65},
+		{HashAlgo: HashAlgoXXH64, HashLen: 9},
+	} {
+		digest, err := conf.NewDigester()
+
+		require.Error(t, err, "algorithm: %s, length: %d", conf.HashAlgo, conf.HashLen)
+		assert.Nil(t, digest, "it should be nil on error")
+	}
+}
+
+// ----------------------------------------------------------------------------
+//  Config.Context
+// ----------------------------------------------------------------------------
+
+func TestConfig_Hash_context(t *testing.T) {
+	t.Parallel()
+
+	const context = "genrawid 2026-10-19 test context"
+
+	for _, size := range []int{0, 5, 1025, 2*lenBlockParallel + 3} {
+		// Input without line breaks to compare with the plain BLAKE3
+		material := []byte(strings.Repeat("abcdefgh", size/8+1)[:size])
+
+		expect := make([]byte, hashLenDefault)
+		blake3.DeriveKey(context, material, expect)
+
+		for _, conf := range []Config{
+			{HashAlgo: HashAlgoBLAKE3, Context: context},
+			{HashAlgo: HashAlgoBLAKE3, Context: context, Concurrency: 2},
+		} {
+			actual, err := conf.Hash(bytes.NewReader(material))
+
+			require.NoError(t, err)
+			assert.Equal(t, expect, []byte(actual), "size: %d, concurrency: %d", size, conf.Concurrency)
+		}
+
+		digest, err := Config{HashAlgo: HashAlgoBLAKE3, Context: context}.NewResumableDigester()
+		require.NoError(t, err)
+
+		_, _ = digest.Write(material)
+
+		actual, err := digest.Sum()
+
+		require.NoError(t, err)
+		assert.Equal(t, expect, actual, "size: %d, resumable", size)
+	}
+}
+
+func TestConfig_Hash_context_differs(t *testing.T) {
+	t.Parallel()
+
+	const input = "abcdefgh"
+
+	digests := map[string]string{}
+
+	for _, context := range []string{"", "context 1", "context 2"} {
+		digest, err := Config{HashAlgo: HashAlgoBLAKE3, Context: context}.Hash(strings.NewReader(input))
+		require.NoError(t, err)
+
+		digests[digest.Hex()] = context
+	}
+
+	assert.Len(t, digests, 3, "the digests should differ for each context")
+}
+
+func TestConfig_Hash_context_not_supported(t *testing.T) {
+	t.Parallel()
+
+	for _, algo := range []THashAlgo{HashAlgoSHA3_512, HashAlgoXXH64} {
+		conf := Config{HashAlgo: algo, Context: "context"}
+
+		digest, err := conf.Hash(strings.NewReader("abcdefgh"))
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "context is supported only by BLAKE3")
+		assert.Nil(t, digest)
+
+		resumable, err := conf.NewResumableDigester()
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "context is supported only by BLAKE3")
+		assert.Nil(t, resumable)
+	}
+}
+
 // ----------------------------------------------------------------------------
 //  Helpers (Dummy reader struct)
 // ----------------------------------------------------------------------------
@@ -0,0 +1,67 @@
+package hasher
+
+import (
+	"encoding"
+
+	"github.com/pkg/errors"
+)
+
+// ErrNotResumable is the error returned if the state of the algorithm can not
+// be saved. Currently SHA3-512 is not resumable.
+var ErrNotResumable = errors.New("the state of the algorithm can not be saved")
+
+// ----------------------------------------------------------------------------
+//  Type: ResumableDigester
+// ----------------------------------------------------------------------------
+
+// ResumableDigester is a Digester which state can be saved with MarshalBinary
+// and restored with UnmarshalBinary later. Such as to resume hashing the input
+// that arrives in chunks over time, without reading the input from the beginning.
+//
+// The saved state must be restored to a ResumableDigester of the same Config.
+type ResumableDigester interface {
+	Digester
+	encoding.BinaryMarshaler
+	encoding.BinaryUnmarshaler
+}
+
+// ----------------------------------------------------------------------------
+//  Methods
+// ----------------------------------------------------------------------------
+
+// NewResumableDigester returns a ResumableDigester of the HashAlgo and HashLen
+// of the Config. It returns ErrNotResumable if the algorithm does not support
+// it.
+//
+// Note that BLAKE3 of ResumableDigester is a pure Go implementation, which is
+// slower than the one of NewDigester. The Concurrency of the Config is ignored
+// as well. The digests are the same though.
+func (c Config) NewResumableDigester() (ResumableDigester, error) {
+	if err := c.checkContext(); err != nil {
+		return nil, err
+	}
+
+	var (
+		digest ResumableDigester
+		err    error
+	)
+
+	switch c.HashAlgo {
+	case HashAlgoBLAKE3:
+		digest, err = newBlake3Resumable(c.HashLen, c.Context)
+	case HashAlgoXXH64:
+		digest, err = newXXHash(c.HashLen)
+	case HashAlgoSHA3_512:
+		err = errors.Wrapf(ErrNotResumable, "%s", c.HashAlgo)
+	case HashAlgoUnknown:
+		fallthrough
+	default:
+		err = newUnknownAlgoError("hash", c.HashAlgo.String())
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return digest, nil
+}
@@ -0,0 +1,72 @@
+package hasher
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfig_NewResumableDigester(t *testing.T) {
+	t.Parallel()
+
+	const (
+		before = "abcd\r"
+		after  = "\nefgh\n"
+	)
+
+	for _, conf := range []Config{
+		{HashAlgo: HashAlgoBLAKE3},
+		{HashAlgo: HashAlgoBLAKE3, HashLen: 32, Concurrency: 2},
+		{HashAlgo: HashAlgoXXH64},
+	} {
+		expect, err := conf.Hash(strings.NewReader(before + after))
+		require.NoError(t, err)
+
+		digest, err := conf.NewResumableDigester()
+		require.NoError(t, err)
+
+		_, _ = digest.Write([]byte(before))
+
+		state, err := digest.MarshalBinary()
+		require.NoError(t, err)
+
+		resumed, err := conf.NewResumableDigester()
+		require.NoError(t, err)
+		require.NoError(t, resumed.UnmarshalBinary(state))
+
+		_, _ = resumed.Write([]byte(after))
+
+		actual, err := resumed.Sum()
+
+		require.NoError(t, err)
+		assert.Equal(t, []byte(expect), actual, "algorithm: %s", conf.HashAlgo)
+	}
+}
+
+func TestConfig_NewResumableDigester_error(t *testing.T) {
+	t.Parallel()
+
+	{
+		digest, err := Config{HashAlgo: HashAlgoSHA3_512}.NewResumableDigester()
+
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, ErrNotResumable), "it should be ErrNotResumable")
+		assert.Contains(t, err.Error(), "sha3-512")
+		assert.Nil(t, digest)
+	}
+
+	for _, conf := range []Config{
+		{HashAlgo: HashAlgoUnknown},
+		{HashAlgo: HashAlgoBLAKE3, HashLen: -1},
+		{HashAlgo: HashAlgoXXH64, HashLen: 9},
+	} {
+		digest, err := conf.NewResumableDigester()
+
+		require.Error(t, err, "algorithm: %s, length: %d", conf.HashAlgo, conf.HashLen)
+		assert.False(t, errors.Is(err, ErrNotResumable))
+		assert.Nil(t, digest, "it should be nil on error")
+	}
+}
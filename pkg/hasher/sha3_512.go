@@ -1,16 +1,37 @@
 package hasher
 
 import (
-	"bufio"
+	"hash"
 	"io"
 
-	"github.com/pkg/errors"
 	"golang.org/x/crypto/sha3"
 )
 
 // The _sha3_512 returns the SHA3-512 hash of input. If lenOut is 0, the output
 // length is 64 bytes.
+//
+// Note that the line breaks of the input are ignored. See lineBreakStripper.
 func _sha3_512(input io.Reader, lenOut int) ([]byte, error) {
+	digest, err := newSHA3512(lenOut)
+	if err != nil {
+		return nil, err
+	}
+
+	return stream(digest, input)
+}
+
+// ----------------------------------------------------------------------------
+//  Type: sha3Digester
+// ----------------------------------------------------------------------------
+
+// sha3Digester is the digester of SHA3-512.
+type sha3Digester struct {
+	hasher   hash.Hash
+	stripper *lineBreakStripper
+	lenOut   int
+}
+
+func newSHA3512(lenOut int) (*sha3Digester, error) {
 	lenMax := 64
 
 	if lenOut == 0 {
@@ -18,27 +39,24 @@ func _sha3_512(input io.Reader, lenOut int) ([]byte, error) {
 	}
 
 	if lenOut < 1 || lenOut > lenMax {
-		return nil, errors.Errorf(
-			"invalid output length. It must be between 1 and %d. Given length: %d",
-			lenMax,
-			lenOut,
-		)
+		return nil, &ErrInvalidLength{Given: lenOut, Min: 1, Max: lenMax}
 	}
 
-	// Create new Hasher that has a digest size of 32 "bytes".
 	sha3Hasher := sha3.New512()
 
-	// Read the input data
-	scanner := bufio.NewScanner(input)
-	for scanner.Scan() {
-		// sha3.state.Write panics if more data is written and never returns an error.
-		_, _ = sha3Hasher.Write(scanner.Bytes())
-	}
+	return &sha3Digester{
+		hasher:   sha3Hasher,
+		stripper: &lineBreakStripper{w: sha3Hasher},
+		lenOut:   lenOut,
+	}, nil
+}
 
-	if err := scanner.Err(); err != nil {
-		return nil, errors.Wrap(err, "failed to read during scanning")
-	}
+// Write implements io.Writer. It never returns an error.
+func (d *sha3Digester) Write(p []byte) (int, error) {
+	return d.stripper.Write(p)
+}
 
-	// Finalize the hash and return the digest.
-	return sha3Hasher.Sum(nil)[:lenOut], nil
+// Sum returns the digest of lenOut bytes of length. It never returns an error.
+func (d *sha3Digester) Sum() ([]byte, error) {
+	return d.hasher.Sum(nil)[:d.lenOut], nil
 }
@@ -82,6 +82,6 @@ func Test_sha3_512_read_error(t *testing.T) {
 	hashByte, err := _sha3_512(d, 0)
 
 	require.Error(t, err)
-	assert.Contains(t, err.Error(), "failed to read during scanning")
+	assert.Contains(t, err.Error(), "failed to read input")
 	assert.Nil(t, hashByte, "return value should be nil on error")
 }
@@ -0,0 +1,132 @@
+package hasher
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"sync"
+)
+
+// ----------------------------------------------------------------------------
+//  Constants and Variables
+// ----------------------------------------------------------------------------
+
+// lenBufStream is the byte size of the buffers to read the input. Which is
+// 128 KiB.
+const lenBufStream = 128 * 1024
+
+// poolBuf is the pool of the buffers to read the input. It avoids allocating
+// a new buffer on every call.
+var poolBuf = sync.Pool{
+	New: func() interface{} {
+		buf := make([]byte, lenBufStream)
+
+		return &buf
+	},
+}
+
+// ----------------------------------------------------------------------------
+//  Type: Digester
+// ----------------------------------------------------------------------------
+
+// Digester is the running state of a hash or checksum algorithm. The input is
+// written to it and Sum returns the result. See Config.NewDigester.
+type Digester interface {
+	io.Writer
+	// Sum returns the digest or the checksum of the data written so far.
+	Sum() ([]byte, error)
+}
+
+// ----------------------------------------------------------------------------
+//  Functions
+// ----------------------------------------------------------------------------
+
+// It writes all the input to the digester and returns its sum. This is the
+// shared streaming core of all the algorithms.
+func stream(dst Digester, input io.Reader) ([]byte, error) {
+	if _, err := copyPooled(dst, input); err != nil {
+		return nil, NewErrRead(err)
+	}
+
+	return dst.Sum()
+}
+
+// It copies src to dst. If src implements io.WriterTo, such as bytes.Reader and
+// strings.Reader, it uses it to avoid copying. Otherwise, it reads src with a
+// buffer from the pool.
+func copyPooled(dst io.Writer, src io.Reader) (int64, error) {
+	// *os.File implements io.WriterTo as well but it falls back to io.Copy with
+	// a newly allocated small buffer unless dst is a network connection.
+	if writerTo, ok := src.(io.WriterTo); ok {
+		if _, isFile := src.(*os.File); !isFile {
+			return writerTo.WriteTo(dst)
+		}
+	}
+
+	ptrBuf, _ := poolBuf.Get().(*[]byte)
+	defer poolBuf.Put(ptrBuf)
+
+	// Wrap src to hide its io.WriterTo from io.CopyBuffer.
+	return io.CopyBuffer(dst, struct{ io.Reader }{src}, *ptrBuf)
+}
+
+// ----------------------------------------------------------------------------
+//  Type: lineBreakStripper
+// ----------------------------------------------------------------------------
+
+// lineBreakStripper is an io.Writer that removes the line breaks, "\n" and
+// "\r\n", from the data before writing to w. A "\r" at the end of the data is
+// removed as well.
+//
+// The BLAKE3 and SHA3-512 digests of this package were used to be computed by
+// reading the input line by line (bufio.ScanLines). It keeps the digests, thus
+// the rawids, the same as before.
+type lineBreakStripper struct {
+	w io.Writer
+	// isPendingCR is true if the last byte written was "\r". Which is removed
+	// if the next byte is "\n" or it was the end of the data.
+	isPendingCR bool
+}
+
+// Write implements io.Writer. It always returns len(p) on success.
+func (s *lineBreakStripper) Write(p []byte) (int, error) {
+	lenP := len(p)
+
+	if lenP > 0 && s.isPendingCR {
+		s.isPendingCR = false
+
+		if p[0] != '\n' {
+			if _, err := s.w.Write([]byte{'\r'}); err != nil {
+				return 0, err
+			}
+		}
+	}
+
+	for len(p) > 0 {
+		line := p
+
+		index := bytes.IndexByte(p, '\n')
+		if index < 0 {
+			p = nil
+		} else {
+			line, p = p[:index], p[index+1:]
+		}
+
+		if lenLine := len(line); lenLine > 0 && line[lenLine-1] == '\r' {
+			line = line[:lenLine-1]
+
+			// Postpone to decide if "\r" is followed by "\n".
+			s.isPendingCR = index < 0
+		}
+
+		if len(line) == 0 {
+			continue
+		}
+
+		if _, err := s.w.Write(line); err != nil {
+			return 0, err
+		}
+	}
+
+	return lenP, nil
+}
@@ -0,0 +1,139 @@
+package hasher
+
+import (
+	"bufio"
+	"bytes"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ----------------------------------------------------------------------------
+//  copyPooled
+// ----------------------------------------------------------------------------
+
+func Test_copyPooled_file(t *testing.T) {
+	t.Parallel()
+
+	// Larger than the buffer size to read more than once.
+	input := bytes.Repeat([]byte("0123456789abcdef"), lenBufStream/8+1)
+	pathFile := filepath.Join(t.TempDir(), "input.bin")
+
+	require.NoError(t, os.WriteFile(pathFile, input, 0o600))
+
+	osFile, err := os.Open(pathFile)
+	require.NoError(t, err)
+
+	defer osFile.Close()
+
+	var output bytes.Buffer
+
+	lenCopied, err := copyPooled(&output, osFile)
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(len(input)), lenCopied)
+	assert.Equal(t, input, output.Bytes())
+}
+
+func Test_copyPooled_writer_to(t *testing.T) {
+	t.Parallel()
+
+	// See hasher_test.go for dummyReader struct. Its WriteTo always errors.
+	d := dummyReader{}
+
+	lenCopied, err := copyPooled(&bytes.Buffer{}, d)
+
+	require.Error(t, err, "it should use io.WriterTo of the input if available")
+	assert.Contains(t, err.Error(), "forced error")
+	assert.Zero(t, lenCopied)
+}
+
+// ----------------------------------------------------------------------------
+//  lineBreakStripper
+// ----------------------------------------------------------------------------
+
+// The result must be the same as reading the input line by line, which was the
+// former implementation of BLAKE3 and SHA3-512 hashing.
+func Test_lineBreakStripper_same_as_scanner(t *testing.T) {
+	t.Parallel()
+
+	//nolint:gosec // weak random is enough for testing
+	rnd := rand.New(rand.NewSource(1))
+	chars := []byte("a\r\n")
+
+	for i := 0; i < 2000; i++ {
+		input := make([]byte, rnd.Intn(32))
+		for j := range input {
+			input[j] = chars[rnd.Intn(len(chars))]
+		}
+
+		expect := scanLines(t, input)
+
+		// Write the input in random sized chunks
+		var output bytes.Buffer
+
+		stripper := &lineBreakStripper{w: &output}
+
+		for data := input; len(data) > 0; {
+			lenChunk := rnd.Intn(len(data)) + 1
+
+			lenWritten, err := stripper.Write(data[:lenChunk])
+			require.NoError(t, err)
+			require.Equal(t, lenChunk, lenWritten)
+
+			data = data[lenChunk:]
+		}
+
+		require.Equal(t, expect, output.String(), "input: %q", input)
+	}
+}
+
+func Test_lineBreakStripper_write_error(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		input       string
+		isPendingCR bool
+	}{
+		{"foo", false}, // error on writing the line
+		{"foo", true},  // error on writing the pending "\r"
+	} {
+		stripper := &lineBreakStripper{w: dummyWriter{}, isPendingCR: test.isPendingCR}
+
+		lenWritten, err := stripper.Write([]byte(test.input))
+
+		require.Error(t, err)
+		assert.Zero(t, lenWritten)
+	}
+}
+
+// ----------------------------------------------------------------------------
+//  Helpers
+// ----------------------------------------------------------------------------
+
+type dummyWriter struct{}
+
+func (w dummyWriter) Write(p []byte) (int, error) {
+	return 0, os.ErrClosed
+}
+
+// It returns the concatenated lines of input read by bufio.ScanLines.
+func scanLines(t *testing.T, input []byte) string {
+	t.Helper()
+
+	var result strings.Builder
+
+	scanner := bufio.NewScanner(bytes.NewReader(input))
+	for scanner.Scan() {
+		result.Write(scanner.Bytes())
+	}
+
+	require.NoError(t, scanner.Err())
+
+	return result.String()
+}
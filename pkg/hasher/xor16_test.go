@@ -0,0 +1,70 @@
+package hasher
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_xor16_golden(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		input  string
+		expect []byte
+	}{
+		{"", []byte{0x00, 0x00}},
+		{"\x01", []byte{0x00, 0x01}},
+		{"\x01\x01\x02\x03\x04\x05", []byte{0x07, 0x07}}, // 0x01^0x03^0x05, 0x01^0x02^0x04
+		{"\x01\x02\x03", []byte{0x02, 0x02}},             // 0x02, 0x01^0x03
+	} {
+		actual, err := _xor16(strings.NewReader(test.input))
+
+		require.NoError(t, err)
+		assert.Equal(t, test.expect, actual, "input: %x", test.input)
+	}
+}
+
+func Test_xor16_read_error(t *testing.T) {
+	t.Parallel()
+
+	// See hasher_test.go for dummyReader2 struct
+	d := dummyReader2{}
+
+	hashByte, err := _xor16(d)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to read input")
+	assert.Nil(t, hashByte, "return value should be nil on error")
+}
+
+func Test_xor16Digester_split_writes(t *testing.T) {
+	t.Parallel()
+
+	input := []byte("The quick brown fox jumps over the lazy dog")
+
+	expect, err := _xor16(bytes.NewReader(input))
+	require.NoError(t, err)
+
+	for lenChunk := 1; lenChunk <= len(input); lenChunk++ {
+		digest := &xor16Digester{}
+
+		for data := input; len(data) > 0; {
+			lenWrite := lenChunk
+			if lenWrite > len(data) {
+				lenWrite = len(data)
+			}
+
+			_, _ = digest.Write(data[:lenWrite])
+			data = data[lenWrite:]
+		}
+
+		actual, err := digest.Sum()
+
+		require.NoError(t, err)
+		require.Equal(t, expect, actual, "chunk size: %d", lenChunk)
+	}
+}
@@ -2,35 +2,44 @@ package hasher
 
 import (
 	"io"
-
-	"github.com/pkg/errors"
 )
 
 // The _xor8 returns a 8bit/1Byte XOR8 checksum from the input.
+//
+// Despite its name, it is the two's complement of the 8 bit sum of the input
+// bytes, a.k.a. LRC (Longitudinal Redundancy Check) as used in Intel HEX. So the
+// sum of the input bytes and the checksum is always zero.
+//
+// It detects any error confined in a single byte. But errors that cancel each
+// other in the sum, such as swapped bytes, are missed and other random errors
+// are missed at a rate of 1 in 256. Which is far weaker than CRC-32C.
 func _xor8(input io.Reader) ([]byte, error) {
-	const mask = uint(0b11111111)
+	return stream(&xor8Digester{}, input)
+}
 
-	sum := uint(0)
-	buffer := make([]byte, 1)
+// ----------------------------------------------------------------------------
+//  Type: xor8Digester
+// ----------------------------------------------------------------------------
 
-	for {
-		lenRead, err := input.Read(buffer)
-		if lenRead > 0 {
-			sum = (sum + uint(buffer[0])) & mask
+// xor8Digester is the digester of XOR8.
+type xor8Digester struct {
+	sum byte
+}
 
-			continue
-		}
+// Write implements io.Writer. It never returns an error.
+func (d *xor8Digester) Write(p []byte) (int, error) {
+	for _, b := range p {
+		d.sum += b // overflows as mod 256
+	}
 
-		if err != nil && err != io.EOF {
-			return nil, errors.Wrap(err, "error during reading data")
-		}
+	return len(p), nil
+}
 
-		if err == io.EOF || lenRead == 0 {
-			break
-		}
-	}
+// Sum returns the 1 Byte checksum. It never returns an error.
+func (d *xor8Digester) Sum() ([]byte, error) {
+	const mask = 0b11111111
 
-	checksum := ((sum ^ mask) + 1) & mask
+	checksum := (d.sum ^ mask) + 1
 
-	return []byte{byte(checksum)}, nil
+	return []byte{checksum}, nil
 }
@@ -42,6 +42,6 @@ func Test_xor8_read_error(t *testing.T) {
 	hashByte, err := _xor8(d)
 
 	require.Error(t, err)
-	assert.Contains(t, err.Error(), "error during reading data")
+	assert.Contains(t, err.Error(), "failed to read input")
 	assert.Nil(t, hashByte, "return value should be nil on error")
 }
@@ -13,32 +13,12 @@ import (
 // The lenOut must be between 0-8. If lenOut is 0(zero) then it will return with
 // the max length 8 Byte.
 func _xxhash(input io.Reader, lenOut int) ([]byte, error) {
-	if err := preCheckXxHash(&lenOut); err != nil {
-		return nil, errors.Wrap(err, "error on _xxhash")
-	}
-
-	xxHasher := xxhash.New()
-	buffer := make([]byte, 1) // set buffer size as 1 Byte to read
-
-	for {
-		lenRead, err := input.Read(buffer) // read input to buffer
-		if lenRead > 0 {
-			// xxhash.Digest.Write always returns len(b), nil and no error
-			_, _ = xxHasher.Write(buffer)
-
-			continue
-		}
-
-		if err != nil && err != io.EOF {
-			return nil, errors.Wrap(err, "error during reading data")
-		}
-
-		if err == io.EOF || lenRead == 0 {
-			break
-		}
+	digest, err := newXXHash(lenOut)
+	if err != nil {
+		return nil, err
 	}
 
-	return xxHasher.Sum(nil)[:lenOut], nil
+	return stream(digest, input)
 }
 
 func preCheckXxHash(lenOut *int) error {
@@ -49,8 +29,35 @@ func preCheckXxHash(lenOut *int) error {
 	}
 
 	if *lenOut > maxLen || *lenOut < 0 {
-		return errors.Errorf("lenOut is too long or short. must be between 0-8. given: %v", lenOut)
+		return &ErrInvalidLength{Given: *lenOut, Min: 1, Max: maxLen}
 	}
 
 	return nil
 }
+
+// ----------------------------------------------------------------------------
+//  Type: xxhashDigester
+// ----------------------------------------------------------------------------
+
+// xxhashDigester is the digester of xxHash (XXH64).
+type xxhashDigester struct {
+	*xxhash.Digest
+	lenOut int
+}
+
+func newXXHash(lenOut int) (*xxhashDigester, error) {
+	if err := preCheckXxHash(&lenOut); err != nil {
+		return nil, errors.Wrap(err, "error on _xxhash")
+	}
+
+	return &xxhashDigester{
+		Digest: xxhash.New(),
+		lenOut: lenOut,
+	}, nil
+}
+
+// Sum returns the hash value of lenOut bytes of length. It never returns an
+// error.
+func (d *xxhashDigester) Sum() ([]byte, error) {
+	return d.Digest.Sum(nil)[:d.lenOut], nil
+}
@@ -81,6 +81,6 @@ func Test_xxhash_read_error(t *testing.T) {
 	hashByte, err := _xxhash(d, 0)
 
 	require.Error(t, err)
-	assert.Contains(t, err.Error(), "error during reading data")
+	assert.Contains(t, err.Error(), "failed to read input")
 	assert.Nil(t, hashByte, "return value should be nil on error")
 }
@@ -29,6 +29,27 @@ func ExampleNewBase62() {
 	// Base62: lYGhA16ahyf
 }
 
+// Parse decodes any of the string representations of rawid.
+func ExampleParse() {
+	for _, input := range []string{
+		"-2474118025671277174", // signed decimal (ID.Dec)
+		"0xddaa2ac39b79058a",   // hex with "0x" prefix (ID.Hex)
+		"j1UNoJA6ku6",          // Base62 (ID.Base62)
+	} {
+		rawID, err := rawid.Parse(input)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		fmt.Println(rawID.Dec())
+	}
+
+	// Output:
+	// -2474118025671277174
+	// -2474118025671277174
+	// -2474118025671277174
+}
+
 // ----------------------------------------------------------------------------
 //  Examples of RawID methods
 // ----------------------------------------------------------------------------
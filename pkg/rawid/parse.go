@@ -0,0 +1,140 @@
+package rawid
+
+import (
+	"encoding/binary"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	lenID    = 8 // byte length of rawid.
+	bitsByte = 8 // bits of a byte.
+)
+
+// ----------------------------------------------------------------------------
+//  Functions
+// ----------------------------------------------------------------------------
+
+// NewDec returns new rawid.ID object from a signed decimal string. Such as the
+// one of ID.Dec.
+func NewDec(dec string) (ID, error) {
+	num, err := strconv.ParseInt(dec, 10, 64)
+	if err != nil {
+		return nil, newErrParse(err, dec, "decimal", isDigitSigned)
+	}
+
+	return newID(uint64(num)), nil
+}
+
+// NewUDec returns new rawid.ID object from an unsigned decimal string. Such as
+// the one of ID.UDec.
+func NewUDec(udec string) (ID, error) {
+	num, err := strconv.ParseUint(udec, 10, 64)
+	if err != nil {
+		return nil, newErrParse(err, udec, "unsigned decimal", isDigit)
+	}
+
+	return newID(num), nil
+}
+
+// NewHex returns new rawid.ID object from a hex string. Such as the one of
+// ID.Hex. The "0x" prefix is optional and the leading zeros may be omitted.
+func NewHex(hex string) (ID, error) {
+	digits := strings.TrimPrefix(strings.TrimPrefix(hex, "0x"), "0X")
+
+	num, err := strconv.ParseUint(digits, 16, 64)
+	if err != nil {
+		errParse := newErrParse(err, digits, "hex", isHexDigit)
+
+		// Set the position in the input with the prefix
+		var errDecode *ErrDecode
+		if errors.As(errParse, &errDecode) {
+			errDecode.Input = hex
+			errDecode.Pos += len(hex) - len(digits)
+		}
+
+		return nil, errParse
+	}
+
+	return newID(num), nil
+}
+
+// Parse returns new rawid.ID object from any of the string representations of
+// rawid. It is decoded as:
+//
+//  1. Hex if it begins with "0x". Such as "0xddaa2ac39b79058a".
+//  2. Signed decimal if it consists of digits with or without "-". Such as
+//     "-2474118025671277174". Or unsigned decimal if it exceeds the range of
+//     int64.
+//  3. Base62 otherwise. Such as "lYGhA16ahyf".
+//
+// Note that Base62 strings of digits only are decoded as decimal. Use NewBase62
+// for such inputs.
+func Parse(input string) (ID, error) {
+	switch {
+	case strings.HasPrefix(input, "0x"), strings.HasPrefix(input, "0X"):
+		return NewHex(input)
+	case input != "" && posInvalid(input, isDigitSigned) == len(input):
+		id, err := NewDec(input)
+		if errors.Is(err, ErrOverRange) && input[0] != '-' {
+			return NewUDec(input)
+		}
+
+		return id, err
+	}
+
+	return NewBase62(input)
+}
+
+// ----------------------------------------------------------------------------
+//  Functions (Private)
+// ----------------------------------------------------------------------------
+
+func newID(num uint64) ID {
+	id := make(ID, lenID)
+
+	binary.BigEndian.PutUint64(id, num)
+
+	return id
+}
+
+// It converts the error of strconv to ErrOverRange or ErrDecode.
+func newErrParse(err error, input, encoding string, isValid func(pos int, char rune) bool) error {
+	if errors.Is(err, strconv.ErrRange) {
+		return errors.Wrapf(ErrOverRange,
+			"the given %s string does not fit in 8 bytes. input: %s", encoding, input)
+	}
+
+	return &ErrDecode{Input: input, Pos: posInvalid(input, isValid), encoding: encoding}
+}
+
+// It returns the byte position of the first invalid character in the input. It
+// is the length of the input if all the characters are valid.
+func posInvalid(input string, isValid func(pos int, char rune) bool) int {
+	for pos, char := range input {
+		if !isValid(pos, char) {
+			return pos
+		}
+	}
+
+	return len(input)
+}
+
+func isDigit(_ int, char rune) bool {
+	return '0' <= char && char <= '9'
+}
+
+// It allows the sign at the beginning as strconv.ParseInt does.
+func isDigitSigned(pos int, char rune) bool {
+	return isDigit(pos, char) || (pos == 0 && (char == '-' || char == '+'))
+}
+
+func isHexDigit(pos int, char rune) bool {
+	return isDigit(pos, char) || ('a' <= char && char <= 'f') || ('A' <= char && char <= 'F')
+}
+
+func isBase62(pos int, char rune) bool {
+	return isDigit(pos, char) || ('a' <= char && char <= 'z') || ('A' <= char && char <= 'Z')
+}
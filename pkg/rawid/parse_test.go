@@ -0,0 +1,100 @@
+package rawid
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	for _, id := range []ID{
+		{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00},
+		{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x0a},
+		{0x80, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00},
+		{0xdd, 0xaa, 0x2a, 0xc3, 0x9b, 0x79, 0x05, 0x8a},
+		{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff},
+	} {
+		for _, input := range []string{
+			id.Dec(),
+			id.UDec(),
+			"0x" + id.Hex(),
+		} {
+			actual, err := Parse(input)
+
+			require.NoError(t, err, "input: %s", input)
+			assert.Equal(t, id, actual, "input: %s", input)
+		}
+	}
+}
+
+func TestParse_base62(t *testing.T) {
+	t.Parallel()
+
+	actual, err := Parse("lYGhA16ahyf")
+	require.NoError(t, err)
+
+	assert.Equal(t, "-1", actual.Dec())
+
+	// Base62 of digits only is decimal. Use NewBase62 instead.
+	actual, err = Parse("10")
+	require.NoError(t, err)
+
+	assert.Equal(t, "10", actual.Dec())
+
+	actual, err = NewBase62("10")
+	require.NoError(t, err)
+
+	assert.Equal(t, "62", actual.Dec())
+	assert.Len(t, actual, 8, "it should be 8 bytes long")
+}
+
+func TestNew_errors(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		parse   func(string) (ID, error)
+		input   string
+		posErr  int
+		isRange bool
+	}{
+		{parse: NewDec, input: "", posErr: 0},
+		{parse: NewDec, input: "-", posErr: 1},
+		{parse: NewDec, input: "12a4", posErr: 2},
+		{parse: NewDec, input: "1-2", posErr: 1},
+		{parse: NewDec, input: "9223372036854775808", isRange: true},
+		{parse: NewDec, input: "-9223372036854775809", isRange: true},
+		{parse: NewUDec, input: "-1", posErr: 0},
+		{parse: NewUDec, input: "18446744073709551616", isRange: true},
+		{parse: NewHex, input: "0x", posErr: 2},
+		{parse: NewHex, input: "0xdg", posErr: 3},
+		{parse: NewHex, input: "xyz", posErr: 0},
+		{parse: NewHex, input: "0x10000000000000000", isRange: true},
+		{parse: NewBase62, input: "+1", posErr: 0},
+		{parse: Parse, input: "", posErr: 0},
+		{parse: Parse, input: "0x1g", posErr: 3},
+		{parse: Parse, input: "-18446744073709551615", isRange: true},
+		{parse: Parse, input: "18446744073709551616", isRange: true},
+		{parse: Parse, input: "abc.def", posErr: 3},
+	} {
+		id, err := test.parse(test.input)
+
+		require.Error(t, err, "input: %q", test.input)
+		assert.Nil(t, id, "it should be nil on error")
+
+		if test.isRange {
+			assert.True(t, errors.Is(err, ErrOverRange), "it should be ErrOverRange. input: %q", test.input)
+
+			continue
+		}
+
+		var errDecode *ErrDecode
+
+		require.True(t, errors.As(err, &errDecode), "it should be ErrDecode. input: %q", test.input)
+		assert.Equal(t, test.input, errDecode.Input)
+		assert.Equal(t, test.posErr, errDecode.Pos, "input: %q", test.input)
+	}
+}
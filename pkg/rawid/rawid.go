@@ -8,33 +8,63 @@ import (
 	"github.com/pkg/errors"
 )
 
+// ----------------------------------------------------------------------------
+//  Errors
+// ----------------------------------------------------------------------------
+
+// ErrOverRange is the error returned if the decoded value does not fit in the
+// 8 bytes of rawid. Use errors.Is to check it.
+var ErrOverRange = errors.New("over range")
+
+// ErrDecode is the error returned if the input could not be decoded as a rawid.
+// Use errors.As to get the details.
+type ErrDecode struct {
+	// Input is the given input.
+	Input string
+	// Pos is the byte position of the first invalid character in Input. It is
+	// the length of Input if it ended too early, such as the empty string.
+	Pos int
+	// encoding is the name of the representation tried to decode.
+	encoding string
+}
+
+// Error implements the error interface.
+func (e *ErrDecode) Error() string {
+	encoding := e.encoding
+	if encoding == "" {
+		encoding = "rawid"
+	}
+
+	return fmt.Sprintf("fail to decode %s input: %s. invalid character at position %d",
+		encoding, e.Input, e.Pos)
+}
+
 // ----------------------------------------------------------------------------
 //  Functions
 // ----------------------------------------------------------------------------
 
-// NewBase62 returns new rawid.ID object from base62 encoded string.
+// NewBase62 returns new rawid.ID object from base62 encoded string. Such as the
+// one of ID.Base62. The returned ID is always 8 bytes long.
 func NewBase62(base62string string) (ID, error) {
-	lenResultMax := 8 // Max byte length of result
-	base62 := 62      // base62
-
-	// Convert base62 string to bytes
-	i := new(big.Int)
+	base62 := 62 // base62
 
-	bInt, ok := i.SetString(base62string, base62)
-	if !ok {
-		return nil, errors.Errorf("fail to decode Base62 input: %s", base62string)
+	// The sign is not a part of Base62 but big.Int accepts it
+	pos := posInvalid(base62string, isBase62)
+	if pos < len(base62string) || base62string == "" {
+		return nil, &ErrDecode{Input: base62string, Pos: pos, encoding: "Base62"}
 	}
 
-	var result ID = bInt.Bytes()
+	// Convert base62 string to bytes
+	bInt, _ := new(big.Int).SetString(base62string, base62)
 
-	if len(result) > lenResultMax {
-		return nil, errors.Errorf(
-			"over range. The given Base62 string has more than 8 bytes after decoding. input: %s",
+	if bInt.BitLen() > lenID*bitsByte {
+		return nil, errors.Wrapf(ErrOverRange,
+			"the given Base62 string has more than 8 bytes after decoding. input: %s",
 			base62string,
 		)
 	}
 
-	return result, nil
+	return newID(bInt.Uint64()), nil
 }
 
 // ----------------------------------------------------------------------------
@@ -3,6 +3,7 @@ package rawid
 import (
 	"testing"
 
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -15,6 +16,7 @@ func TestNewBase62_over_range(t *testing.T) {
 
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "over range")
+	assert.True(t, errors.Is(err, ErrOverRange), "it should be ErrOverRange")
 	assert.Nil(t, id, "returned value should be nil on error")
 }
 
@@ -28,3 +30,24 @@ func TestNewBase62_unknown_string(t *testing.T) {
 	assert.Contains(t, err.Error(), "fail to decode Base62 input")
 	assert.Nil(t, id, "returned value should be nil on error")
 }
+
+func TestNewBase62_error_position(t *testing.T) {
+	t.Parallel()
+
+	for input, expect := range map[string]int{
+		"":       0,
+		"&":      0,
+		"abc&de": 3,
+		"abc-":   3,
+		"Zz9_":   3,
+	} {
+		_, err := NewBase62(input)
+
+		var errDecode *ErrDecode
+
+		require.True(t, errors.As(err, &errDecode), "it should be ErrDecode. input: %q", input)
+		assert.Equal(t, input, errDecode.Input)
+		assert.Equal(t, expect, errDecode.Pos, "input: %q", input)
+		assert.False(t, errors.Is(err, ErrOverRange))
+	}
+}
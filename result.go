@@ -0,0 +1,137 @@
+package genrawid
+
+import (
+	"io"
+	"os"
+
+	"github.com/KEINOS/go-genrawid/pkg/hasher"
+	"github.com/KEINOS/go-genrawid/pkg/rawid"
+	"github.com/pkg/errors"
+)
+
+// ----------------------------------------------------------------------------
+//  Type: Result
+// ----------------------------------------------------------------------------
+
+// Result holds the rawid and the values it was made of.
+//
+// Since the rawid is only 8 Bytes, different inputs may have the same rawid.
+// The full digest can be used to tell them apart.
+type Result struct {
+	// ID is the generated rawid.
+	ID rawid.ID
+	// Digest is the full hash digest of the input. In fast mode, it is the
+	// 8 Byte digest of XXH64, which is the same as ID.
+	Digest []byte
+	// CheckSum is the checksum of Digest. It is nil in fast mode.
+	CheckSum []byte
+	// Size is the byte length of the input read. Including the line breaks that
+	// the hash algorithm may ignore.
+	Size int64
+	// Scheme is the name of the scheme used. See Generator.Scheme.
+	Scheme string
+}
+
+// ----------------------------------------------------------------------------
+//  Functions (Public)
+// ----------------------------------------------------------------------------
+
+// Compute returns the Result of the input using the current settings of the
+// package variables.
+func Compute(input io.Reader) (Result, error) {
+	return newFromGlobals().Compute(input)
+}
+
+// ComputeFile returns the Result of the input file using the current settings
+// of the package variables.
+func ComputeFile(path string) (Result, error) {
+	return newFromGlobals().ComputeFile(path)
+}
+
+// ----------------------------------------------------------------------------
+//  Methods
+// ----------------------------------------------------------------------------
+
+// Compute returns the Result of the input. It is the same as FromReader but
+// with the full digest, the checksum, the input size and the scheme name.
+func (g *Generator) Compute(input io.Reader) (Result, error) {
+	counter := newSizeCounter(input)
+
+	// Calculate hash value.
+	hashByte, err := g.hashConfig().Hash(counter.reader())
+	if err != nil {
+		return Result{}, errors.Wrap(err, "failed to generate rawid")
+	}
+
+	return g.newResult(hashByte, counter.size)
+}
+
+// ComputeFile returns the Result of the input file. It is the same as FromFile
+// but with the full digest, the checksum, the input size and the scheme name.
+func (g *Generator) ComputeFile(path string) (Result, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return Result{}, errors.Wrap(hasher.NewErrRead(err), "failed to open file")
+	}
+
+	defer file.Close()
+
+	return g.computeOsFile(file)
+}
+
+// ----------------------------------------------------------------------------
+//  Type: sizeCounter
+// ----------------------------------------------------------------------------
+
+// sizeCounter is an io.Reader that counts the bytes read from the input.
+type sizeCounter struct {
+	input io.Reader
+	size  int64
+}
+
+// sizeCounterWriterTo is a sizeCounter that keeps io.WriterTo of the input.
+// Which lets the hasher package write the input, such as bytes.Reader of a
+// memory-mapped file, without copying.
+type sizeCounterWriterTo struct {
+	*sizeCounter
+}
+
+func newSizeCounter(input io.Reader) *sizeCounter {
+	return &sizeCounter{input: input}
+}
+
+// Read implements io.Reader.
+func (c *sizeCounter) Read(p []byte) (int, error) {
+	n, err := c.input.Read(p)
+	c.size += int64(n)
+
+	return n, err
+}
+
+// It returns the reader to pass to the hasher package. It is nil if the input
+// is nil, so that the hasher package can detect it.
+//
+// *os.File is excluded from io.WriterTo since the hasher package reads it with
+// its own buffers.
+func (c *sizeCounter) reader() io.Reader {
+	if c.input == nil {
+		return nil
+	}
+
+	if _, isFile := c.input.(*os.File); !isFile {
+		if _, ok := c.input.(io.WriterTo); ok {
+			return sizeCounterWriterTo{c}
+		}
+	}
+
+	return c
+}
+
+// WriteTo implements io.WriterTo.
+func (c sizeCounterWriterTo) WriteTo(w io.Writer) (int64, error) {
+	//nolint:forcetypeassert // checked in reader()
+	n, err := c.input.(io.WriterTo).WriteTo(w)
+	c.size += n
+
+	return n, err
+}
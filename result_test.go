@@ -0,0 +1,94 @@
+package genrawid
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/KEINOS/go-genrawid/pkg/hasher"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerator_Compute(t *testing.T) {
+	t.Parallel()
+
+	result, err := New().Compute(strings.NewReader("abcdefgh"))
+	require.NoError(t, err)
+
+	assert.Equal(t, "ddaa2ac39b79058a", result.ID.Hex())
+	assert.Len(t, result.Digest, 64)
+	assert.Equal(t, []byte{0xdd, 0xaa, 0x2a, 0xc3}, result.Digest[:4], "the rawid should begin with the digest")
+	assert.Equal(t, []byte{0x9b, 0x79, 0x05, 0x8a}, result.CheckSum, "the rawid should end with the checksum")
+	assert.Equal(t, int64(8), result.Size)
+	assert.Equal(t, "v1/blake3/512/crc32c", result.Scheme)
+}
+
+func TestGenerator_Compute_size_with_line_breaks(t *testing.T) {
+	t.Parallel()
+
+	// The size counts the line breaks even though BLAKE3 ignores them.
+	result, err := New().Compute(strings.NewReader("abcd\r\nefgh\n"))
+	require.NoError(t, err)
+
+	assert.Equal(t, "ddaa2ac39b79058a", result.ID.Hex())
+	assert.Equal(t, int64(11), result.Size)
+}
+
+func TestGenerator_Compute_fast_mode(t *testing.T) {
+	t.Parallel()
+
+	result, err := New(WithFastMode(true)).Compute(strings.NewReader("abcdefgh"))
+	require.NoError(t, err)
+
+	assert.Equal(t, "3ad351775b4634b7", result.ID.Hex())
+	assert.Equal(t, []byte(result.ID), result.Digest)
+	assert.Nil(t, result.CheckSum)
+	assert.Equal(t, int64(8), result.Size)
+	assert.Equal(t, SchemeFast, result.Scheme)
+}
+
+func TestGenerator_Compute_checksum_length(t *testing.T) {
+	t.Parallel()
+
+	result, err := New(WithChkSumAlgo(hasher.ChkSumXOR16)).Compute(strings.NewReader("abcdefgh"))
+	require.NoError(t, err)
+
+	assert.Equal(t, "ddaa2ac30a98963b", result.ID.Hex())
+	assert.Equal(t, []byte{0x96, 0x3b}, result.CheckSum)
+	assert.Equal(t, "v1/blake3/512/xor16", result.Scheme)
+}
+
+func TestGenerator_Compute_nil_input(t *testing.T) {
+	t.Parallel()
+
+	for _, isFast := range []bool{false, true} {
+		result, err := New(WithFastMode(isFast)).Compute(nil)
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "nil pointer for input given")
+		assert.Equal(t, Result{}, result, "it should be zero value on error")
+	}
+}
+
+func TestGenerator_ComputeFile(t *testing.T) {
+	t.Parallel()
+
+	// Both memory-mapped and streamed
+	for _, threshold := range []int64{0, -1} {
+		result, err := New(WithMmapThreshold(threshold)).ComputeFile("testdata/dummy.bin")
+		require.NoError(t, err)
+
+		assert.Equal(t, "-2929669798473946006", result.ID.Dec(), "threshold: %d", threshold)
+		assert.Equal(t, int64(5e6), result.Size, "threshold: %d", threshold)
+	}
+}
+
+func TestGenerator_ComputeFile_file_not_found(t *testing.T) {
+	t.Parallel()
+
+	result, err := New().ComputeFile("dummy/unknown/file")
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to open file")
+	assert.Equal(t, Result{}, result, "it should be zero value on error")
+}
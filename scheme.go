@@ -0,0 +1,212 @@
+package genrawid
+
+import (
+	"fmt"
+	"hash/crc32"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/KEINOS/go-genrawid/pkg/hasher"
+	"github.com/pkg/errors"
+)
+
+// ----------------------------------------------------------------------------
+//  Constants
+// ----------------------------------------------------------------------------
+
+// SchemeFast is the scheme name of the fast mode.
+//
+// The fast mode uses the 8 Byte digest of XXH64 as the rawid as is. Its rawids
+// are not compatible with the regular ones nor the ones of the former fast mode,
+// which is "v1/blake3/512/xor16" now.
+const SchemeFast = "fast-v2/xxh64"
+
+// SchemeDefault is the scheme name of the default settings. Which is BLAKE3 of
+// 64 Bytes with CRC-32C as the checksum.
+const SchemeDefault = "v1/blake3/512/crc32c"
+
+// schemeVersion is the version prefix of the regular scheme names.
+const schemeVersion = "v1"
+
+// ----------------------------------------------------------------------------
+//  Functions (Public)
+// ----------------------------------------------------------------------------
+
+// ParseScheme returns a new Generator of the given scheme name. The name is the
+// one returned by Generator.Scheme, such as "v1/sha3-512/512/crc32c". Also
+// "default" and "fast" are accepted as the aliases of SchemeDefault and
+// SchemeFast.
+//
+// The other settings, such as the concurrency, are the same as New.
+func ParseScheme(name string) (*Generator, error) {
+	const (
+		aliasDefault = "default"
+		aliasFast    = "fast"
+		bitsByte     = 8
+		numParts     = 4
+	)
+
+	switch name {
+	case aliasFast, SchemeFast:
+		return New(WithFastMode(true)), nil
+	case aliasDefault:
+		name = SchemeDefault
+	}
+
+	parts := strings.Split(name, "/")
+	if len(parts) != numParts || parts[0] != schemeVersion {
+		return nil, errors.Errorf("invalid scheme name: %s", name)
+	}
+
+	hashAlgo, context, err := parseNameHash(parts[1])
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid scheme name: %s", name)
+	}
+
+	bits, err := strconv.Atoi(parts[2])
+	if err != nil || bits < bitsByte || bits%bitsByte != 0 {
+		return nil, errors.Errorf("invalid scheme name: %s. the bits of digest must be a multiple of 8", name)
+	}
+
+	chkSumAlgo, poly, err := parseNameChkSum(parts[3])
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid scheme name: %s", name)
+	}
+
+	return New(
+		WithFastMode(false),
+		WithHashAlgo(hashAlgo),
+		WithHashLen(bits/bitsByte),
+		WithChkSumAlgo(chkSumAlgo),
+		WithCRC32Poly(poly),
+		WithContext(context),
+	), nil
+}
+
+// ----------------------------------------------------------------------------
+//  Methods
+// ----------------------------------------------------------------------------
+
+// Scheme returns the name of the scheme, the set of algorithms, that the
+// Generator uses. rawids generated with different schemes are not comparable.
+//
+// It is SchemeFast in fast mode. Otherwise the name is in the form of
+// "v1/<hash>/<bits of digest>/<checksum>". E.g. "v1/blake3/512/crc32c" for the
+// default settings.
+//
+// If the context of the derive-key mode is set, the hash is followed by ":" and
+// the URL path escaped context. E.g. "v1/blake3:acme%202026%20manifest%20v1/512/crc32c".
+func (g *Generator) Scheme() string {
+	if g.isFast {
+		return SchemeFast
+	}
+
+	const bitsByte = 8
+
+	nameHash := g.conf.HashAlgo.String()
+	if g.conf.Context != "" {
+		nameHash += ":" + url.PathEscape(g.conf.Context)
+	}
+
+	return fmt.Sprintf("%s/%s/%d/%s",
+		schemeVersion,
+		nameHash,
+		lenHash(g.conf.HashAlgo, g.conf.HashLen)*bitsByte,
+		nameChkSum(g.conf.ChkSumAlgo, g.conf.CRC32Poly),
+	)
+}
+
+// ----------------------------------------------------------------------------
+//  Functions (Private)
+// ----------------------------------------------------------------------------
+
+// It returns the actual byte length of the hash digest. The hasher package
+// treats 0 as the default length of the algorithm.
+func lenHash(algo hasher.THashAlgo, lenGiven int) int {
+	const (
+		lenDefault = 64
+		lenXXH64   = 8
+	)
+
+	switch {
+	case lenGiven != 0:
+		return lenGiven
+	case algo == hasher.HashAlgoXXH64:
+		return lenXXH64
+	default:
+		return lenDefault
+	}
+}
+
+// It returns the hash algorithm and the context of the derive-key mode of the
+// name in the scheme name. Such as "blake3" or "blake3:<escaped context>".
+func parseNameHash(name string) (hasher.THashAlgo, string, error) {
+	const numParts = 2
+
+	parts := strings.SplitN(name, ":", numParts)
+
+	algo, err := hasher.ParseHashAlgo(parts[0])
+	if err != nil || len(parts) == 1 {
+		return algo, "", err
+	}
+
+	if algo != hasher.HashAlgoBLAKE3 {
+		return hasher.HashAlgoUnknown, "", errors.Errorf("context is supported only by BLAKE3: %s", name)
+	}
+
+	context, err := url.PathUnescape(parts[1])
+	if err != nil || context == "" {
+		return hasher.HashAlgoUnknown, "", errors.Errorf("invalid context: %s", parts[1])
+	}
+
+	return algo, context, nil
+}
+
+// It returns the checksum algorithm and the polynomial of the name in the scheme
+// name. This is the reverse of nameChkSum. The polynomial is the default one if
+// the algorithm is not CRC32.
+func parseNameChkSum(name string) (hasher.TChkSumAlgo, uint32, error) {
+	const prefixPoly = "crc32-"
+
+	switch name {
+	case "crc32c":
+		return hasher.ChkSumCRC32, crc32.Castagnoli, nil
+	case "crc32":
+		return hasher.ChkSumCRC32, crc32.IEEE, nil
+	case "crc32k":
+		return hasher.ChkSumCRC32, crc32.Koopman, nil
+	}
+
+	if strings.HasPrefix(name, prefixPoly) {
+		poly, err := strconv.ParseUint(strings.TrimPrefix(name, prefixPoly), 16, 32)
+		if err != nil {
+			return hasher.ChkSumUnknown, 0, errors.Errorf("invalid polynomial of CRC32: %s", name)
+		}
+
+		return hasher.ChkSumCRC32, uint32(poly), nil
+	}
+
+	algo, err := hasher.ParseChkSumAlgo(name)
+
+	return algo, crc32.Castagnoli, err
+}
+
+// It returns the name of the checksum algorithm in the scheme name. For CRC32
+// the polynomial is distinguished.
+func nameChkSum(algo hasher.TChkSumAlgo, poly uint32) string {
+	if algo != hasher.ChkSumCRC32 {
+		return algo.String()
+	}
+
+	switch poly {
+	case crc32.Castagnoli:
+		return "crc32c"
+	case crc32.IEEE:
+		return "crc32"
+	case crc32.Koopman:
+		return "crc32k"
+	}
+
+	return fmt.Sprintf("crc32-%08x", poly)
+}
@@ -0,0 +1,104 @@
+package genrawid
+
+import (
+	"hash/crc32"
+	"testing"
+
+	"github.com/KEINOS/go-genrawid/pkg/hasher"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerator_Scheme(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		expect string
+		opts   []Option
+	}{
+		{"v1/blake3/512/crc32c", nil},
+		{"v1/blake3/512/crc32", []Option{WithCRC32Poly(crc32.IEEE)}},
+		{"v1/blake3/512/crc32k", []Option{WithCRC32Poly(crc32.Koopman)}},
+		{"v1/blake3/512/crc32-12345678", []Option{WithCRC32Poly(0x12345678)}},
+		{"v1/blake3/512/xxhash", []Option{WithCRC32Poly(crc32.IEEE), WithChkSumAlgo(hasher.ChkSumXXHash)}},
+		{"v1/blake3/512/xor8", []Option{WithChkSumAlgo(hasher.ChkSumXOR8)}},
+		{"v1/blake3/512/xor16", []Option{WithChkSumAlgo(hasher.ChkSumXOR16)}},
+		{"v1/blake3/512/crc32c", []Option{WithHashLen(0)}},
+		{"v1/blake3/256/crc32c", []Option{WithHashLen(32)}},
+		{"v1/sha3-512/512/crc32c", []Option{WithHashAlgo(hasher.HashAlgoSHA3_512)}},
+		{"v1/xxh64/64/crc32c", []Option{WithHashAlgo(hasher.HashAlgoXXH64), WithHashLen(0)}},
+		{"v1/blake3:acme%202026%20manifest%20v1/512/crc32c", []Option{WithContext("acme 2026 manifest v1")}},
+		{"v1/blake3:a%2Fb:c/256/crc32c", []Option{WithContext("a/b:c"), WithHashLen(32)}},
+		{SchemeFast, []Option{WithHashAlgo(hasher.HashAlgoSHA3_512), WithFastMode(true)}},
+	} {
+		actual := New(test.opts...).Scheme()
+
+		assert.Equal(t, test.expect, actual)
+	}
+}
+
+func TestParseScheme(t *testing.T) {
+	t.Parallel()
+
+	for _, name := range []string{
+		"v1/blake3/512/crc32c",
+		"v1/blake3/512/crc32",
+		"v1/blake3/512/crc32k",
+		"v1/blake3/512/crc32-12345678",
+		"v1/blake3/512/xxhash",
+		"v1/blake3/512/xor8",
+		"v1/blake3/512/xor16",
+		"v1/blake3/256/crc32c",
+		"v1/sha3-512/512/crc32c",
+		"v1/xxh64/64/crc32c",
+		"v1/blake3:acme%202026%20manifest%20v1/512/crc32c",
+		"v1/blake3:a%2Fb:c/256/crc32c",
+		SchemeFast,
+	} {
+		gen, err := ParseScheme(name)
+
+		require.NoError(t, err, "scheme: %s", name)
+		assert.Equal(t, name, gen.Scheme(), "it should be the reverse of Generator.Scheme")
+	}
+}
+
+func TestParseScheme_aliases(t *testing.T) {
+	t.Parallel()
+
+	for alias, expect := range map[string]string{
+		"default": SchemeDefault,
+		"fast":    SchemeFast,
+	} {
+		gen, err := ParseScheme(alias)
+
+		require.NoError(t, err, "alias: %s", alias)
+		assert.Equal(t, expect, gen.Scheme(), "alias: %s", alias)
+	}
+}
+
+func TestParseScheme_invalid(t *testing.T) {
+	t.Parallel()
+
+	for _, name := range []string{
+		"",
+		"blake3",
+		"v2/blake3/512/crc32c",
+		"v1/blake3/512",
+		"v1/blake3/512/crc32c/extra",
+		"v1/md5/128/crc32c",
+		"v1/blake3/foo/crc32c",
+		"v1/blake3/0/crc32c",
+		"v1/blake3/12/crc32c",
+		"v1/blake3/512/crc64",
+		"v1/blake3/512/crc32-xyz",
+		"v1/blake3:/512/crc32c",
+		"v1/blake3:%zz/512/crc32c",
+		"v1/sha3-512:foo/512/crc32c",
+	} {
+		gen, err := ParseScheme(name)
+
+		require.Error(t, err, "scheme: %q", name)
+		assert.Contains(t, err.Error(), "invalid scheme name", "scheme: %q", name)
+		assert.Nil(t, gen, "it should be nil on error")
+	}
+}
@@ -0,0 +1,163 @@
+package genrawid
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/binary"
+
+	"github.com/KEINOS/go-genrawid/pkg/hasher"
+	"github.com/pkg/errors"
+)
+
+// magicWriter is the prefix of the saved state of Writer. The last byte is the
+// version of the format.
+const magicWriter = "rawid-w\x01"
+
+// ----------------------------------------------------------------------------
+//  Type: Writer
+// ----------------------------------------------------------------------------
+
+// Writer computes the rawid of the data written to it. It is useful if the input
+// arrives in parts, such as uploads.
+//
+// Its state can be saved with MarshalBinary and restored with UnmarshalBinary
+// to resume later, if the hash algorithm supports it. Such as BLAKE3 and fast
+// mode (XXH64). SHA3-512 does not support it.
+type Writer struct {
+	gen    *Generator
+	digest hasher.Digester
+	size   int64
+}
+
+// NewWriter returns a new Writer using the current settings of the package
+// variables.
+func NewWriter() (*Writer, error) {
+	return newFromGlobals().NewWriter()
+}
+
+// NewWriter returns a new Writer of the Generator.
+//
+// Note that BLAKE3 of Writer is a pure Go implementation to save its state. It
+// is slower than the other methods of Generator, though the rawids are the same.
+func (g *Generator) NewWriter() (*Writer, error) {
+	conf := g.hashConfig()
+
+	var digest hasher.Digester
+
+	digest, err := conf.NewResumableDigester()
+	if errors.Is(err, hasher.ErrNotResumable) {
+		digest, err = conf.NewDigester()
+	}
+
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create writer")
+	}
+
+	return &Writer{
+		gen:    g,
+		digest: digest,
+	}, nil
+}
+
+// ----------------------------------------------------------------------------
+//  Methods
+// ----------------------------------------------------------------------------
+
+// Write implements io.Writer. It never returns an error.
+func (w *Writer) Write(p []byte) (int, error) {
+	n, err := w.digest.Write(p)
+	w.size += int64(n)
+
+	return n, err
+}
+
+// Result returns the Result of the data written so far. More data can be written
+// after that.
+func (w *Writer) Result() (Result, error) {
+	digest, err := w.digest.Sum()
+	if err != nil {
+		return Result{}, errors.Wrap(err, "failed to generate rawid")
+	}
+
+	return w.gen.newResult(digest, w.size)
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler. It returns the state of the
+// Writer, which includes the scheme name and the byte size written so far.
+//
+// It returns an error which wraps hasher.ErrNotResumable if the hash algorithm
+// does not support it.
+func (w *Writer) MarshalBinary() ([]byte, error) {
+	marshaler, ok := w.digest.(encoding.BinaryMarshaler)
+	if !ok {
+		return nil, errors.Wrapf(hasher.ErrNotResumable, "scheme %s", w.gen.Scheme())
+	}
+
+	state, err := marshaler.MarshalBinary()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to save the state")
+	}
+
+	scheme := w.gen.Scheme()
+
+	var buf bytes.Buffer
+
+	buf.WriteString(magicWriter)
+
+	// Writing to bytes.Buffer never fails
+	_ = binary.Write(&buf, binary.BigEndian, uint16(len(scheme)))
+
+	buf.WriteString(scheme)
+
+	_ = binary.Write(&buf, binary.BigEndian, w.size)
+
+	buf.Write(state)
+
+	return buf.Bytes(), nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler. It restores the state
+// saved by MarshalBinary. The Writer must be of the same scheme as the saved one.
+func (w *Writer) UnmarshalBinary(data []byte) error {
+	unmarshaler, ok := w.digest.(encoding.BinaryUnmarshaler)
+	if !ok {
+		return errors.Wrapf(hasher.ErrNotResumable, "scheme %s", w.gen.Scheme())
+	}
+
+	if !bytes.HasPrefix(data, []byte(magicWriter)) {
+		return errors.New("failed to restore the state: unknown format")
+	}
+
+	reader := bytes.NewReader(data[len(magicWriter):])
+
+	var lenScheme uint16
+	if err := binary.Read(reader, binary.BigEndian, &lenScheme); err != nil {
+		return errors.Wrap(err, "failed to restore the state")
+	}
+
+	scheme := make([]byte, lenScheme)
+	if err := binary.Read(reader, binary.BigEndian, scheme); err != nil {
+		return errors.Wrap(err, "failed to restore the state")
+	}
+
+	if string(scheme) != w.gen.Scheme() {
+		return errors.Errorf(
+			"failed to restore the state: the state is of a different scheme. saved: %s, writer: %s",
+			scheme,
+			w.gen.Scheme(),
+		)
+	}
+
+	var size int64
+	if err := binary.Read(reader, binary.BigEndian, &size); err != nil {
+		return errors.Wrap(err, "failed to restore the state")
+	}
+
+	if err := unmarshaler.UnmarshalBinary(data[len(data)-reader.Len():]); err != nil {
+		return errors.Wrap(err, "failed to restore the state")
+	}
+
+	w.size = size
+
+	return nil
+}
@@ -0,0 +1,137 @@
+package genrawid
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/KEINOS/go-genrawid/pkg/hasher"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriter_resume(t *testing.T) {
+	t.Parallel()
+
+	const input = "abcd\r\nefgh\n"
+
+	for _, gen := range []*Generator{
+		New(),
+		New(WithFastMode(true)),
+		New(WithHashLen(32), WithChkSumAlgo(hasher.ChkSumXOR16)),
+	} {
+		expect, err := gen.Compute(strings.NewReader(input))
+		require.NoError(t, err)
+
+		for split := 0; split <= len(input); split++ {
+			writer, err := gen.NewWriter()
+			require.NoError(t, err)
+
+			_, err = writer.Write([]byte(input[:split]))
+			require.NoError(t, err)
+
+			state, err := writer.MarshalBinary()
+			require.NoError(t, err)
+
+			// Resume with a new Writer
+			resumed, err := gen.NewWriter()
+			require.NoError(t, err)
+			require.NoError(t, resumed.UnmarshalBinary(state))
+
+			_, err = resumed.Write([]byte(input[split:]))
+			require.NoError(t, err)
+
+			actual, err := resumed.Result()
+			require.NoError(t, err)
+
+			assert.Equal(t, expect, actual, "scheme: %s, split: %d", gen.Scheme(), split)
+		}
+	}
+}
+
+func TestWriter_not_resumable(t *testing.T) {
+	t.Parallel()
+
+	gen := New(WithHashAlgo(hasher.HashAlgoSHA3_512))
+
+	// It can compute the rawid but can not save the state
+	writer, err := gen.NewWriter()
+	require.NoError(t, err)
+
+	_, err = writer.Write([]byte("abcdefgh"))
+	require.NoError(t, err)
+
+	result, err := writer.Result()
+	require.NoError(t, err)
+
+	assert.Equal(t, "v1/sha3-512/512/crc32c", result.Scheme)
+	assert.Equal(t, int64(8), result.Size)
+
+	state, err := writer.MarshalBinary()
+
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, hasher.ErrNotResumable), "it should be hasher.ErrNotResumable")
+	assert.Contains(t, err.Error(), "scheme v1/sha3-512/512/crc32c")
+	assert.Nil(t, state)
+
+	err = writer.UnmarshalBinary([]byte("dummy"))
+
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, hasher.ErrNotResumable), "it should be hasher.ErrNotResumable")
+}
+
+func TestWriter_UnmarshalBinary_error(t *testing.T) {
+	t.Parallel()
+
+	writer, err := New().NewWriter()
+	require.NoError(t, err)
+
+	_, _ = writer.Write([]byte("abcdefgh"))
+
+	state, err := writer.MarshalBinary()
+	require.NoError(t, err)
+
+	stateFast, err := func() ([]byte, error) {
+		writerFast, err := New(WithFastMode(true)).NewWriter()
+		require.NoError(t, err)
+
+		return writerFast.MarshalBinary()
+	}()
+	require.NoError(t, err)
+
+	for _, test := range []struct {
+		expect string
+		state  []byte
+	}{
+		{expect: "unknown format", state: []byte("foo")},
+		{expect: "unexpected EOF", state: state[:len(magicWriter)+1]},
+		{expect: "unexpected EOF", state: state[:len(magicWriter)+4]},
+		{expect: "different scheme. saved: fast-v2/xxh64, writer: v1/blake3/512/crc32c", state: stateFast},
+		{expect: "unexpected EOF", state: state[:len(magicWriter)+2+len(SchemeDefault)+3]},
+		{expect: "invalid state of BLAKE3", state: state[:len(state)-1]},
+	} {
+		err := writer.UnmarshalBinary(test.state)
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to restore the state")
+		assert.Contains(t, err.Error(), test.expect)
+	}
+
+	// The state should be kept on error
+	result, err := writer.Result()
+	require.NoError(t, err)
+
+	assert.Equal(t, "ddaa2ac39b79058a", result.ID.Hex())
+	assert.Equal(t, int64(8), result.Size)
+}
+
+func TestGenerator_NewWriter_error(t *testing.T) {
+	t.Parallel()
+
+	writer, err := New(WithHashAlgo(hasher.HashAlgoUnknown)).NewWriter()
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to create writer")
+	assert.Contains(t, err.Error(), "unknown hash algorithm")
+	assert.Nil(t, writer)
+}